@@ -0,0 +1,241 @@
+// Package actor 基于goroutine和channel的简单actor运行时
+//
+// 每个actor有一个goroutine和一个邮箱（带缓冲的channel），按顺序处理消息，
+// 状态只在自己的goroutine里访问，因此不需要锁。
+// 消息类型由泛型参数确定；请求/响应用 Ask 实现，消息里携带回复用的channel。
+// actor处理消息时panic，会按 Options 中的监督策略用全新的状态重启。
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+)
+
+// ErrStopped actor已停止时发送消息返回
+var ErrStopped = errors.New("actor已停止")
+
+// Actor 处理M类型消息的actor
+type Actor[M any] interface {
+	Receive(ctx context.Context, msg M)
+}
+
+// Func 把函数适配为 Actor
+type Func[M any] func(ctx context.Context, msg M)
+
+func (f Func[M]) Receive(ctx context.Context, msg M) { f(ctx, msg) }
+
+// Options actor选项
+type Options struct {
+	Mailbox int // 邮箱容量，满时发送方阻塞
+
+	// MaxRestarts 在 Within 时间内最多重启的次数，超过后actor停止；
+	// 0表示panic后不重启直接停止，负数表示总是重启
+	MaxRestarts int
+	Within      time.Duration // 为0时统计全部重启次数
+
+	// OnFailure 每次panic时调用（可选），restarted表示是否会重启
+	OnFailure func(name string, panicValue any, restarted bool)
+}
+
+// System 管理一组actor的生命周期
+type System struct {
+	clock  clock.Clock // 监督策略按它统计时间窗口内的重启次数
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	actors map[string]stopper
+	wg     sync.WaitGroup
+	closed bool
+}
+
+type stopper interface{ stop() }
+
+// NewSystem 创建actor系统，clk为nil时使用真实时钟
+func NewSystem(clk clock.Clock) *System {
+	if clk == nil {
+		clk = clock.Real
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &System{clock: clk, ctx: ctx, cancel: cancel, actors: make(map[string]stopper)}
+}
+
+// Ref actor的引用，只能通过它给actor发消息
+type Ref[M any] struct {
+	name    string
+	mu      sync.RWMutex
+	closed  bool
+	mailbox chan M
+	done    chan struct{}
+}
+
+// Spawn 启动一个actor，factory在启动和每次重启时调用，返回全新的状态
+func Spawn[M any](sys *System, name string, factory func() Actor[M], opts Options) (*Ref[M], error) {
+	ref := &Ref[M]{name: name, mailbox: make(chan M, opts.Mailbox), done: make(chan struct{})}
+
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	if sys.closed {
+		return nil, ErrStopped
+	}
+	if _, ok := sys.actors[name]; ok {
+		return nil, fmt.Errorf("actor %s 已存在", name)
+	}
+	sys.actors[name] = ref
+
+	sys.wg.Add(1)
+	go func() {
+		defer sys.wg.Done()
+		supervise(sys.ctx, sys.clock, ref, factory, opts)
+
+		// 先唤醒阻塞在满邮箱上的发送者（它们持有读锁），再关闭邮箱
+		// 放弃重启时邮箱中剩下的消息被丢弃
+		close(ref.done)
+		ref.Stop()
+		sys.forget(name, ref)
+	}()
+	return ref, nil
+}
+
+// Name 返回actor的名称
+func (r *Ref[M]) Name() string { return r.name }
+
+// Send 把消息放入邮箱，邮箱满时等待，ctx取消时返回ctx的错误
+func (r *Ref[M]) Send(ctx context.Context, msg M) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return ErrStopped
+	}
+	select {
+	case <-r.done:
+		return ErrStopped
+	default:
+	}
+	select {
+	case r.mailbox <- msg:
+		return nil
+	case <-r.done:
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop 不再接收新消息，actor处理完邮箱里已有的消息后退出
+func (r *Ref[M]) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		close(r.mailbox)
+	}
+}
+
+func (r *Ref[M]) stop() { r.Stop() }
+
+// Done 返回actor退出后关闭的channel
+func (r *Ref[M]) Done() <-chan struct{} { return r.done }
+
+// Ask 发送一个请求并等待回复
+// build用回复channel构造请求消息，actor处理时把结果写入这个channel（带1个缓冲，写入不会阻塞）
+// 处理这条请求时actor发生panic就不会有回复，因此ctx应当带有超时
+func Ask[M, R any](ctx context.Context, ref *Ref[M], build func(reply chan<- R) M) (R, error) {
+	reply := make(chan R, 1)
+	var zero R
+	if err := ref.Send(ctx, build(reply)); err != nil {
+		return zero, err
+	}
+	select {
+	case r := <-reply:
+		return r, nil
+	case <-ref.done:
+		return zero, ErrStopped
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// supervise 运行actor，panic时按策略重启
+func supervise[M any](ctx context.Context, clk clock.Clock, ref *Ref[M], factory func() Actor[M], opts Options) {
+	var restarts []time.Time
+	for {
+		panicValue, panicked := run(ctx, ref, factory())
+		if !panicked {
+			return // 邮箱已关闭并处理完
+		}
+
+		now := clk.Now()
+		if opts.Within > 0 {
+			// 只保留时间窗口内的重启记录
+			kept := restarts[:0]
+			for _, t := range restarts {
+				if now.Sub(t) < opts.Within {
+					kept = append(kept, t)
+				}
+			}
+			restarts = kept
+		}
+		restart := opts.MaxRestarts < 0 || len(restarts) < opts.MaxRestarts
+		if opts.OnFailure != nil {
+			opts.OnFailure(ref.name, panicValue, restart)
+		}
+		if !restart {
+			return
+		}
+		restarts = append(restarts, now)
+	}
+}
+
+// run 处理消息直到邮箱关闭，或某条消息的处理发生panic
+// 导致panic的消息被丢弃，其余消息留在邮箱里由重启后的actor继续处理
+func run[M any](ctx context.Context, ref *Ref[M], a Actor[M]) (panicValue any, panicked bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			panicValue, panicked = v, true
+		}
+	}()
+	for msg := range ref.mailbox {
+		a.Receive(ctx, msg)
+	}
+	return nil, false
+}
+
+func (s *System) forget(name string, ref stopper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.actors[name] == ref {
+		delete(s.actors, name)
+	}
+}
+
+// Shutdown 停止所有actor：不再接收新消息，等它们处理完邮箱中的消息
+// ctx到期时取消传给 Receive 的ctx并立即返回ctx的错误，不等忽略ctx的actor退出
+func (s *System) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for _, a := range s.actors {
+		a.stop()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
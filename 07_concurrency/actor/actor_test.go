@@ -0,0 +1,299 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+	"go-learn/07_concurrency/leakcheck"
+)
+
+// msg 测试用的消息：reply不为nil时回复当前计数，crash为true时panic，hang为true时不回复
+type msg struct {
+	crash bool
+	hang  bool
+	reply chan<- int
+}
+
+// counter 每收到一条消息计数加一，重启后从0开始
+type counter struct{ n int }
+
+func (c *counter) Receive(ctx context.Context, m msg) {
+	switch {
+	case m.crash:
+		panic("崩溃")
+	case m.hang:
+		return
+	case m.reply != nil:
+		m.reply <- c.n
+	default:
+		c.n++
+	}
+}
+
+func newCounter() Actor[msg] { return &counter{} }
+
+// newSystem 创建使用假时钟的系统，测试结束时关闭并检查goroutine泄漏
+func newSystem(t *testing.T) (*System, *clock.Fake) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	sys := NewSystem(fake)
+	t.Cleanup(func() {
+		if err := sys.Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	})
+	return sys, fake
+}
+
+func count(ctx context.Context, ref *Ref[msg]) (int, error) {
+	return Ask(ctx, ref, func(reply chan<- int) msg { return msg{reply: reply} })
+}
+
+// failures 把每次panic时 OnFailure 的 restarted 参数发到channel
+func failures() (chan bool, func(string, any, bool)) {
+	c := make(chan bool, 10)
+	return c, func(name string, v any, restarted bool) { c <- restarted }
+}
+
+func TestSendAndAsk(t *testing.T) {
+	sys, _ := newSystem(t)
+	ref, err := Spawn(sys, "counter", newCounter, Options{Mailbox: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Name() != "counter" {
+		t.Errorf("Name = %q", ref.Name())
+	}
+	if _, err := Spawn(sys, "counter", newCounter, Options{}); err == nil {
+		t.Error("重复的名称应返回错误")
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		if err := ref.Send(ctx, msg{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := count(ctx, ref); n != 10 || err != nil {
+		t.Errorf("count = %d, %v，期望 10", n, err)
+	}
+}
+
+func TestAskTimeout(t *testing.T) {
+	sys, _ := newSystem(t)
+	ref, _ := Spawn(sys, "silent", newCounter, Options{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := Ask(ctx, ref, func(reply chan<- int) msg { return msg{hang: true, reply: reply} })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ask 返回 %v，期望 DeadlineExceeded", err)
+	}
+
+	// 超时不影响之后的请求
+	if _, err := count(context.Background(), ref); err != nil {
+		t.Errorf("超时之后 Ask 返回 %v", err)
+	}
+}
+
+// TestAskCrashWithoutRestart 处理请求时崩溃且不重启，Ask 返回 ErrStopped 而不是一直等待
+func TestAskCrashWithoutRestart(t *testing.T) {
+	sys, _ := newSystem(t)
+	ref, _ := Spawn(sys, "fragile", newCounter, Options{})
+
+	_, err := Ask(context.Background(), ref, func(reply chan<- int) msg { return msg{crash: true, reply: reply} })
+	if !errors.Is(err, ErrStopped) {
+		t.Errorf("Ask 返回 %v，期望 ErrStopped", err)
+	}
+	<-ref.Done()
+	if err := ref.Send(context.Background(), msg{}); !errors.Is(err, ErrStopped) {
+		t.Errorf("停止后 Send 返回 %v，期望 ErrStopped", err)
+	}
+}
+
+func TestRestartFreshState(t *testing.T) {
+	sys, _ := newSystem(t)
+	failed, onFailure := failures()
+	ref, _ := Spawn(sys, "counter", newCounter, Options{Mailbox: 8, MaxRestarts: -1, OnFailure: onFailure})
+
+	ctx := context.Background()
+	ref.Send(ctx, msg{})
+	ref.Send(ctx, msg{})
+	ref.Send(ctx, msg{crash: true})
+	ref.Send(ctx, msg{}) // 崩溃后剩下的消息由重启后的actor继续处理
+	if !<-failed {
+		t.Fatal("MaxRestarts 为负数时应总是重启")
+	}
+	if n, err := count(ctx, ref); n != 1 || err != nil {
+		t.Errorf("重启后 count = %d, %v，期望状态重置后只计了1条", n, err)
+	}
+}
+
+// TestRestartLimitWithin 时间窗口内的重启次数超过上限后停止，窗口外的重启不计入
+func TestRestartLimitWithin(t *testing.T) {
+	sys, fake := newSystem(t)
+	failed, onFailure := failures()
+	ref, _ := Spawn(sys, "limited", newCounter, Options{
+		Mailbox:     8,
+		MaxRestarts: 2,
+		Within:      time.Minute,
+		OnFailure:   onFailure,
+	})
+	ctx := context.Background()
+	crash := func() bool {
+		t.Helper()
+		if err := ref.Send(ctx, msg{crash: true}); err != nil {
+			t.Fatal(err)
+		}
+		return <-failed
+	}
+
+	if !crash() || !crash() {
+		t.Fatal("前2次崩溃应重启")
+	}
+	fake.Advance(time.Minute) // 之前的重启移出时间窗口
+	if !crash() || !crash() {
+		t.Fatal("时间窗口之外的重启不应计入上限")
+	}
+	fake.Advance(30 * time.Second)
+	if crash() {
+		t.Fatal("1分钟内第3次崩溃不应重启")
+	}
+	select {
+	case <-ref.Done():
+	case <-time.After(time.Second):
+		t.Fatal("放弃重启后actor应停止")
+	}
+}
+
+// TestRestartLimitTotal Within为0时统计全部重启次数，时间流逝也不会重置
+func TestRestartLimitTotal(t *testing.T) {
+	sys, fake := newSystem(t)
+	failed, onFailure := failures()
+	ref, _ := Spawn(sys, "limited", newCounter, Options{Mailbox: 8, MaxRestarts: 1, OnFailure: onFailure})
+
+	ref.Send(context.Background(), msg{crash: true})
+	if !<-failed {
+		t.Fatal("第1次崩溃应重启")
+	}
+	fake.Advance(24 * time.Hour)
+	ref.Send(context.Background(), msg{crash: true})
+	if <-failed {
+		t.Fatal("Within 为0时第2次崩溃不应重启")
+	}
+	<-ref.Done()
+}
+
+// TestBlockedSenderWokenOnStop 邮箱满时阻塞的发送者在actor停止后收到 ErrStopped
+func TestBlockedSenderWokenOnStop(t *testing.T) {
+	sys, _ := newSystem(t)
+	release := make(chan struct{})
+	ref, _ := Spawn(sys, "stuck", func() Actor[msg] {
+		return Func[msg](func(ctx context.Context, m msg) {
+			<-release
+			panic("崩溃")
+		})
+	}, Options{Mailbox: 1})
+
+	ctx := context.Background()
+	ref.Send(ctx, msg{}) // 正在处理
+	ref.Send(ctx, msg{}) // 占满邮箱
+	errc := make(chan error, 1)
+	go func() { errc <- ref.Send(ctx, msg{}) }()
+
+	close(release)
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrStopped) {
+			t.Errorf("Send 返回 %v，期望 ErrStopped", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("actor停止后发送者仍在阻塞")
+	}
+}
+
+func TestShutdownDrainsMailbox(t *testing.T) {
+	leakcheck.Verify(t)
+	sys := NewSystem(nil)
+	processed := make(chan int, 100)
+	ref, _ := Spawn(sys, "worker", func() Actor[int] {
+		return Func[int](func(ctx context.Context, n int) { processed <- n })
+	}, Options{Mailbox: 100})
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		ref.Send(ctx, i)
+	}
+	if err := sys.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(processed) != 100 {
+		t.Errorf("Shutdown 前处理了 %d 条消息，期望全部 100 条", len(processed))
+	}
+
+	if err := ref.Send(ctx, 1); !errors.Is(err, ErrStopped) {
+		t.Errorf("Shutdown 后 Send 返回 %v，期望 ErrStopped", err)
+	}
+	if _, err := Spawn(sys, "late", newCounter, Options{}); !errors.Is(err, ErrStopped) {
+		t.Errorf("Shutdown 后 Spawn 返回 %v，期望 ErrStopped", err)
+	}
+}
+
+// TestShutdownTimeout 超时后取消传给 Receive 的ctx，等待ctx的actor随之退出
+func TestShutdownTimeout(t *testing.T) {
+	leakcheck.Verify(t)
+	sys := NewSystem(nil)
+	started := make(chan struct{})
+	ref, _ := Spawn(sys, "slow", func() Actor[msg] {
+		return Func[msg](func(ctx context.Context, m msg) {
+			close(started)
+			<-ctx.Done()
+		})
+	}, Options{})
+	ref.Send(context.Background(), msg{})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sys.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown 返回 %v，期望 DeadlineExceeded", err)
+	}
+	select {
+	case <-ref.Done():
+	case <-time.After(time.Second):
+		t.Error("ctx被取消后actor应退出")
+	}
+}
+
+// TestShutdownTimeoutIgnoredCtx actor不理会ctx时，Shutdown 仍在ctx到期后按时返回
+func TestShutdownTimeoutIgnoredCtx(t *testing.T) {
+	leakcheck.Verify(t)
+	sys := NewSystem(nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release) // 测试结束时放行，避免泄漏检查失败
+	ref, _ := Spawn(sys, "stubborn", func() Actor[msg] {
+		return Func[msg](func(ctx context.Context, m msg) {
+			close(started)
+			<-release
+		})
+	}, Options{})
+	ref.Send(context.Background(), msg{})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- sys.Shutdown(ctx) }()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Shutdown 返回 %v，期望 DeadlineExceeded", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("actor忽略ctx时 Shutdown 没有在超时后返回")
+	}
+}
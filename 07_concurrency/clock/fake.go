@@ -0,0 +1,195 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake 手动推进的假时钟，可被多个goroutine并发使用
+//
+// 被测代码在另一个goroutine里 Sleep 时，先用 BlockUntil 等它真正开始等待，
+// 再调用 Advance，否则可能在它开始等待之前就推进了时间。
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter 一个等待中的Timer、Ticker或Sleep
+type fakeWaiter struct {
+	clock    *Fake
+	deadline time.Time
+	period   time.Duration // Ticker的周期，Timer为0
+	ch       chan time.Time
+	fn       func() // AfterFunc的回调
+}
+
+// NewFake 创建从start开始的假时钟
+func NewFake(start time.Time) *Fake {
+	f := &Fake{now: start}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration { return f.Now().Sub(t) }
+
+func (f *Fake) Sleep(d time.Duration) { <-f.After(d) }
+
+func (f *Fake) After(d time.Duration) <-chan time.Time { return f.NewTimer(d).C() }
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.add(&fakeWaiter{clock: f, ch: make(chan time.Time, 1)}, d)
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.add(&fakeWaiter{clock: f, fn: fn}, d)
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: 非正数的Ticker周期")
+	}
+	return fakeTicker{f.add(&fakeWaiter{clock: f, ch: make(chan time.Time, 1), period: d}, d)}
+}
+
+// add 登记waiter，d<=0 时立即触发
+func (f *Fake) add(w *fakeWaiter, d time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addLocked(w, d)
+}
+
+// addLocked 同 add，调用者必须持有f.mu
+func (f *Fake) addLocked(w *fakeWaiter, d time.Duration) *fakeWaiter {
+	w.deadline = f.now.Add(d)
+	if d <= 0 && w.period == 0 {
+		w.fire(f.now)
+		return w
+	}
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+	return w
+}
+
+// remove 取消登记，返回waiter是否还在等待
+func (f *Fake) remove(w *fakeWaiter) bool {
+	for i, x := range f.waiters {
+		if x == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance 把时间推进d，按到期顺序触发期间到期的所有Timer和Ticker
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	target := f.now.Add(d)
+
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool {
+			return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+		})
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(target) {
+			break
+		}
+
+		w := f.waiters[0]
+		f.now = w.deadline
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+		} else {
+			f.waiters = f.waiters[1:]
+		}
+		w.fire(f.now)
+	}
+
+	f.now = target
+	f.cond.Broadcast()
+}
+
+// Waiters 返回正在等待的Timer、Ticker和Sleep的数量
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// NextDeadline 返回最早到期的等待者的到期时间，没有等待者时ok为false
+func (f *Fake) NextDeadline() (deadline time.Time, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, w := range f.waiters {
+		if !ok || w.deadline.Before(deadline) {
+			deadline, ok = w.deadline, true
+		}
+	}
+	return deadline, ok
+}
+
+// BlockUntil 阻塞直到至少有n个等待者
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// fire 触发一次；和time包一样，channel满时丢弃这次触发，不阻塞时钟
+func (w *fakeWaiter) fire(now time.Time) {
+	if w.fn != nil {
+		go w.fn()
+		return
+	}
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	active := w.clock.remove(w)
+	w.clock.cond.Broadcast()
+	return active
+}
+
+// Reset 对Timer重新设置到期时间，对Ticker重新设置周期
+// 取消和重新登记在同一次加锁内完成，Advance 不会看到中间状态
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	f := w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	active := f.remove(w)
+	if w.period > 0 {
+		w.period = d
+	}
+	f.addLocked(w, d)
+	return active
+}
+
+// fakeTicker 适配 Ticker 接口，Stop和Reset不返回值
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+func (t fakeTicker) Stop()               { t.w.Stop() }
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: 非正数的Ticker周期")
+	}
+	t.w.Reset(d)
+}
@@ -0,0 +1,264 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fired 非阻塞地检查channel是否已经触发
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	f := NewFake(epoch)
+	f.Advance(90 * time.Minute)
+	if got := f.Now(); !got.Equal(epoch.Add(90 * time.Minute)) {
+		t.Errorf("Now = %v", got)
+	}
+	if got := f.Since(epoch); got != 90*time.Minute {
+		t.Errorf("Since = %v", got)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Second)
+
+	f.Advance(999 * time.Millisecond)
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("Timer提前触发")
+	}
+	f.Advance(time.Millisecond)
+	at, ok := fired(timer.C())
+	if !ok {
+		t.Fatal("Timer没有触发")
+	}
+	if !at.Equal(epoch.Add(time.Second)) {
+		t.Errorf("触发时间 = %v，期望到期时间", at)
+	}
+	if timer.Stop() {
+		t.Error("已触发的Timer Stop 应返回false")
+	}
+	if f.Waiters() != 0 {
+		t.Errorf("Waiters = %d", f.Waiters())
+	}
+}
+
+func TestTimerZeroDuration(t *testing.T) {
+	f := NewFake(epoch)
+	if _, ok := fired(f.After(0)); !ok {
+		t.Error("After(0) 应立即触发")
+	}
+}
+
+func TestTimerStop(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Second)
+	if !timer.Stop() {
+		t.Error("等待中的Timer Stop 应返回true")
+	}
+	f.Advance(time.Hour)
+	if _, ok := fired(timer.C()); ok {
+		t.Error("停止的Timer不应触发")
+	}
+}
+
+func TestTimerReset(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Second)
+	f.Advance(500 * time.Millisecond)
+	if !timer.Reset(time.Second) {
+		t.Error("等待中的Timer Reset 应返回true")
+	}
+
+	f.Advance(900 * time.Millisecond) // 原来的到期时间已过
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("Reset 之后按原来的时间触发了")
+	}
+	f.Advance(100 * time.Millisecond)
+	if _, ok := fired(timer.C()); !ok {
+		t.Fatal("Reset 之后没有按新时间触发")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("已触发的Timer Reset 应返回false")
+	}
+	f.Advance(time.Second)
+	if _, ok := fired(timer.C()); !ok {
+		t.Error("重新启用的Timer没有触发")
+	}
+}
+
+func TestAdvanceFiresAtDeadlines(t *testing.T) {
+	f := NewFake(epoch)
+	timers := map[time.Duration]Timer{}
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		timers[d] = f.NewTimer(d)
+	}
+
+	// 一次推进越过所有到期时间，每个Timer收到的仍是各自的到期时间
+	f.Advance(time.Minute)
+	for d, timer := range timers {
+		at, ok := fired(timer.C())
+		if !ok {
+			t.Errorf("%v 的Timer没有触发", d)
+			continue
+		}
+		if !at.Equal(epoch.Add(d)) {
+			t.Errorf("%v 的Timer触发时间 = %v", d, at)
+		}
+	}
+	if !f.Now().Equal(epoch.Add(time.Minute)) {
+		t.Errorf("Now = %v", f.Now())
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	f := NewFake(epoch)
+	called := make(chan time.Time, 1)
+	f.AfterFunc(time.Second, func() { called <- f.Now() })
+	f.Advance(time.Second)
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("回调没有被调用")
+	}
+}
+
+func TestAfterFuncStop(t *testing.T) {
+	f := NewFake(epoch)
+	called := make(chan struct{}, 1)
+	timer := f.AfterFunc(time.Second, func() { called <- struct{}{} })
+	if timer.C() != nil {
+		t.Error("AfterFunc 的Timer不应有channel")
+	}
+	timer.Stop()
+	f.Advance(time.Hour)
+	select {
+	case <-called:
+		t.Error("停止后回调仍被调用")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTicker(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		f.Advance(time.Second)
+		at, ok := fired(ticker.C())
+		if !ok {
+			t.Fatalf("第%d次没有触发", i)
+		}
+		if !at.Equal(epoch.Add(time.Duration(i) * time.Second)) {
+			t.Errorf("第%d次触发时间 = %v", i, at)
+		}
+	}
+
+	// 和time.Ticker一样，读取跟不上时丢弃多余的触发
+	f.Advance(5 * time.Second)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Fatal("没有触发")
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("channel中积压了多次触发")
+	}
+}
+
+func TestTickerReset(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Second)
+	ticker.Reset(3 * time.Second)
+
+	f.Advance(2 * time.Second)
+	if _, ok := fired(ticker.C()); ok {
+		t.Fatal("Reset 之后仍按原周期触发")
+	}
+	f.Advance(time.Second)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Fatal("没有按新周期触发")
+	}
+
+	ticker.Stop()
+	f.Advance(time.Hour)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("停止后仍然触发")
+	}
+}
+
+func TestTickerRejectsNonPositive(t *testing.T) {
+	f := NewFake(epoch)
+	defer func() {
+		if recover() == nil {
+			t.Error("非正数的周期应panic")
+		}
+	}()
+	f.NewTicker(0)
+}
+
+func TestSleepAndBlockUntil(t *testing.T) {
+	f := NewFake(epoch)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.Sleep(time.Minute)
+	}()
+
+	f.BlockUntil(1) // Sleep 已经登记，这时推进不会错过它
+	f.Advance(time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sleep 没有返回")
+	}
+}
+
+func TestNextDeadline(t *testing.T) {
+	f := NewFake(epoch)
+	if _, ok := f.NextDeadline(); ok {
+		t.Error("没有等待者时 ok 应为false")
+	}
+	f.NewTimer(2 * time.Second)
+	f.NewTimer(time.Second)
+	if d, ok := f.NextDeadline(); !ok || !d.Equal(epoch.Add(time.Second)) {
+		t.Errorf("NextDeadline = %v, %v", d, ok)
+	}
+}
+
+// TestResetIsAtomic Reset 期间其他goroutine不应看到Timer暂时不在等待
+func TestResetIsAtomic(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Hour)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				timer.Reset(time.Hour)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000000; i++ {
+		if n := f.Waiters(); n != 1 {
+			t.Errorf("Reset 过程中 Waiters = %d", n)
+			break
+		}
+	}
+	close(stop)
+	<-done
+}
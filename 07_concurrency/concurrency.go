@@ -2,51 +2,72 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go-learn/07_concurrency/actor"
+	"go-learn/07_concurrency/clock"
+	counters "go-learn/07_concurrency/counter"
+	"go-learn/07_concurrency/gen"
+	"go-learn/07_concurrency/leakcheck"
+	"go-learn/07_concurrency/pipeline"
+	"go-learn/07_concurrency/pool"
+	"go-learn/07_concurrency/primitives"
+	"go-learn/07_concurrency/pubsub"
+	"go-learn/07_concurrency/scheduler"
 )
 
+// 示例中所有的等待都通过传入的clk进行，换成假时钟后不必真的等待
+
 // 1. 简单的goroutine示例
-func sayHello(name string) {
+func sayHello(clk clock.Clock, name string) {
 	for i := 0; i < 3; i++ {
 		fmt.Printf("Hello %s! (%d)\n", name, i+1)
-		time.Sleep(100 * time.Millisecond)
+		clk.Sleep(100 * time.Millisecond)
 	}
 }
 
 // 2. 使用channel通信的goroutine
-func producer(ch chan<- int, name string) {
+func producer(clk clock.Clock, ch chan<- int, name string) {
 	for i := 1; i <= 5; i++ {
 		fmt.Printf("%s 生产了 %d\n", name, i)
 		ch <- i
-		time.Sleep(200 * time.Millisecond)
+		clk.Sleep(200 * time.Millisecond)
 	}
 	close(ch)
 }
 
-func consumer(ch <-chan int, name string) {
+func consumer(clk clock.Clock, ch <-chan int, name string) {
 	for value := range ch {
 		fmt.Printf("%s 消费了 %d\n", name, value)
-		time.Sleep(150 * time.Millisecond)
+		clk.Sleep(150 * time.Millisecond)
 	}
 }
 
-// 3. 工作池模式
-func worker(id int, jobs <-chan int, results chan<- int) {
-	for job := range jobs {
+// 3. 工作池模式：worker只负责处理单个任务，调度交给 pool 包
+func worker(clk clock.Clock) pool.Task[int, int] {
+	return func(ctx context.Context, job int) (int, error) {
+		id := pool.WorkerID(ctx)
 		fmt.Printf("Worker %d 开始处理 job %d\n", id, job)
-		time.Sleep(500 * time.Millisecond) // 模拟工作
-		results <- job * 2                 // 返回结果
+		if job == 4 {
+			return 0, errors.New("job 4 数据有误") // 模拟失败的任务
+		}
+		clk.Sleep(500 * time.Millisecond) // 模拟工作
 		fmt.Printf("Worker %d 完成了 job %d\n", id, job)
+		return job * 2, nil // 返回结果
 	}
 }
 
 // 4. 使用sync.WaitGroup
-func taskWithWaitGroup(id int, wg *sync.WaitGroup) {
+func taskWithWaitGroup(clk clock.Clock, id int, wg *sync.WaitGroup) {
 	defer wg.Done() // 任务完成时调用
 	fmt.Printf("任务 %d 开始\n", id)
-	time.Sleep(time.Duration(id*100) * time.Millisecond)
+	clk.Sleep(time.Duration(id*100) * time.Millisecond)
 	fmt.Printf("任务 %d 完成\n", id)
 }
 
@@ -75,35 +96,83 @@ func incrementCounter(counter *Counter, times int, wg *sync.WaitGroup) {
 	}
 }
 
-// 6. select语句示例
-func fibonacci(c, quit chan int) {
-	x, y := 0, 1
-	for {
-		select {
-		case c <- x:
-			x, y = y, x+y
-		case <-quit:
-			fmt.Println("斐波那契生成器退出")
-			return
-		case <-time.After(2 * time.Second):
-			fmt.Println("超时，斐波那契生成器退出")
-			return
-		}
+// counterMsg 计数器actor的消息：reply为nil时加delta，否则把当前值写入reply
+type counterMsg struct {
+	delta int
+	reply chan<- int
+}
+
+// counterActor 与Counter相同的计数器，状态只在actor自己的goroutine里访问，不需要Mutex
+type counterActor struct {
+	value int
+}
+
+func (c *counterActor) Receive(ctx context.Context, msg counterMsg) {
+	if msg.reply != nil {
+		msg.reply <- c.value
+		return
 	}
+	c.value += msg.delta
 }
 
-func main() {
-	fmt.Println("=== Go语言并发编程 ===")
+// accountMsg 账户actor能处理的消息，用未导出的方法把消息类型限定在下面几种
+type accountMsg interface{ isAccountMsg() }
 
-	// 1. 基本goroutine
-	fmt.Println("\n1. 基本goroutine:")
-	go sayHello("Alice")
-	go sayHello("Bob")
-	time.Sleep(500 * time.Millisecond) // 等待goroutines完成
+type deposit struct{ amount int }
 
-	// 2. channel基础
-	fmt.Println("\n2. Channel基础:")
+type withdraw struct {
+	amount int
+	reply  chan<- error
+}
 
+type balance struct{ reply chan<- int }
+
+type audit struct{} // 模拟一个会让actor崩溃的消息
+
+func (deposit) isAccountMsg()  {}
+func (withdraw) isAccountMsg() {}
+func (balance) isAccountMsg()  {}
+func (audit) isAccountMsg()    {}
+
+// accountActor 银行账户，余额不会因为并发存取而出错
+type accountActor struct {
+	balance int
+}
+
+func (a *accountActor) Receive(ctx context.Context, msg accountMsg) {
+	switch m := msg.(type) {
+	case deposit:
+		a.balance += m.amount
+	case withdraw:
+		if m.amount > a.balance {
+			m.reply <- fmt.Errorf("余额不足: 余额 %d，取款 %d", a.balance, m.amount)
+			return
+		}
+		a.balance -= m.amount
+		m.reply <- nil
+	case balance:
+		m.reply <- a.balance
+	case audit:
+		panic("审计数据损坏")
+	}
+}
+
+// 1. 基本goroutine
+func demoGoroutines(clk clock.Clock) {
+	// 用WaitGroup等待，而不是估计一个sleep时长
+	var wg sync.WaitGroup
+	for _, name := range []string{"Alice", "Bob"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sayHello(clk, name)
+		}(name)
+	}
+	wg.Wait() // 等待goroutines完成
+}
+
+// 2. channel基础
+func demoChannels() {
 	// 无缓冲channel
 	ch := make(chan string)
 	go func() {
@@ -121,81 +190,122 @@ func main() {
 	fmt.Printf("从缓冲channel读取: %d\n", <-bufferedCh)
 	fmt.Printf("从缓冲channel读取: %d\n", <-bufferedCh)
 	fmt.Printf("从缓冲channel读取: %d\n", <-bufferedCh)
+}
 
-	// 3. 生产者-消费者模式
-	fmt.Println("\n3. 生产者-消费者模式:")
+// 3. 生产者-消费者模式
+func demoProducerConsumer(clk clock.Clock) {
 	productCh := make(chan int)
+	go producer(clk, productCh, "生产者A")
+
+	// 中间接一个流水线阶段：产品加工成平方后再交给消费者
+	// 生产者关闭channel后，关闭会沿着流水线传递，consumer的range随之结束，无需sleep等待
+	ctx := context.Background()
+	squared := pipeline.Map(ctx, productCh, func(n int) int { return n * n })
+	consumer(clk, squared, "消费者1")
+
+	// 更多阶段的组合：过滤偶数、两路并行加工后合并、按2个一批输出
+	evens := pipeline.Filter(ctx, pipeline.From(ctx, 1, 2, 3, 4, 5, 6, 7, 8), func(n int) bool { return n%2 == 0 })
+	lanes := pipeline.FanOut(ctx, evens, 2)
+	merged := pipeline.FanIn(ctx,
+		pipeline.Map(ctx, lanes[0], func(n int) int { return n * 10 }),
+		pipeline.Map(ctx, lanes[1], func(n int) int { return n * 10 }))
+	for batch := range pipeline.Batch(ctx, merged, 2, 0) {
+		fmt.Println("批次:", batch)
+	}
+}
 
-	go producer(productCh, "生产者A")
-	go consumer(productCh, "消费者1")
-
-	time.Sleep(2 * time.Second)
-
-	// 4. 工作池模式
-	fmt.Println("\n4. 工作池模式:")
+// 4. 工作池模式
+func demoWorkerPool(clk clock.Clock) {
 	const numJobs = 5
 	const numWorkers = 3
 
-	jobs := make(chan int, numJobs)
-	results := make(chan int, numJobs)
+	// 启动工作者，结果按提交顺序输出
+	p := pool.New(context.Background(), worker(clk), pool.Options{Workers: numWorkers, Ordered: true})
 
-	// 启动工作者
-	for w := 1; w <= numWorkers; w++ {
-		go worker(w, jobs, results)
-	}
+	// 发送工作，发送完后关闭工作池
+	go func() {
+		defer p.Shutdown()
+		for j := 1; j <= numJobs; j++ {
+			p.Submit(j)
+		}
+	}()
 
-	// 发送工作
-	for j := 1; j <= numJobs; j++ {
-		jobs <- j
+	// 收集结果，Results 在所有工作完成后关闭，不需要事先知道工作数量
+	for r := range p.Results() {
+		if r.Err != nil {
+			fmt.Printf("job %d 失败: %v\n", r.Input, r.Err)
+			continue
+		}
+		fmt.Printf("收到结果: %d\n", r.Value)
 	}
-	close(jobs)
-
-	// 收集结果
-	for a := 1; a <= numJobs; a++ {
-		result := <-results
-		fmt.Printf("收到结果: %d\n", result)
+	if err := p.Wait(); err != nil {
+		fmt.Println("工作池错误汇总:", err)
 	}
+}
 
-	// 5. 使用WaitGroup
-	fmt.Println("\n5. 使用WaitGroup:")
+// 5. 使用WaitGroup
+func demoWaitGroup(clk clock.Clock) {
 	var wg sync.WaitGroup
 
 	for i := 1; i <= 3; i++ {
 		wg.Add(1) // 增加等待的goroutine数量
-		go taskWithWaitGroup(i, &wg)
+		go taskWithWaitGroup(clk, i, &wg)
 	}
 
 	wg.Wait() // 等待所有goroutine完成
 	fmt.Println("所有任务完成")
+}
 
-	// 6. 使用Mutex保护共享资源
-	fmt.Println("\n6. 使用Mutex保护共享资源:")
+// 6. 使用Mutex保护共享资源
+func demoMutex() {
 	counter := &Counter{}
-	var wg2 sync.WaitGroup
+	var wg sync.WaitGroup
 
 	// 启动多个goroutine同时增加计数器
 	for i := 0; i < 3; i++ {
-		wg2.Add(1)
-		go incrementCounter(counter, 100, &wg2)
+		wg.Add(1)
+		go incrementCounter(counter, 100, &wg)
 	}
 
-	wg2.Wait()
+	wg.Wait()
 	fmt.Printf("最终计数器值: %d\n", counter.Value())
 
-	// 7. select语句
-	fmt.Println("\n7. Select语句:")
+	// 同一接口下的其他实现：原子操作、分片计数，以及按名称管理的计数器
+	// 性能比较: go test -bench . -cpu 1,2,4,8 ./07_concurrency/counter
+	impls := map[string]counters.Counter{
+		"atomic":  &counters.AtomicCounter{},
+		"sharded": counters.NewSharded(),
+	}
+	stats := counters.NewRegistry(nil)
+	for name, c := range impls {
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(name string, c counters.Counter) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					c.Increment()
+					stats.Add(name+".increments", 1)
+				}
+			}(name, c)
+		}
+	}
+	wg.Wait()
+	fmt.Println("注册表快照:", stats.Snapshot())
+}
 
-	// 非阻塞的channel操作
-	ch1 := make(chan string)
-	ch2 := make(chan string)
+// 7. select语句
+func demoSelect(clk clock.Clock) {
+	// 带1个缓冲：即使select因超时不再读取，发送方也不会永远阻塞
+	ch1 := make(chan string, 1)
+	ch2 := make(chan string, 1)
 
 	go func() {
-		time.Sleep(100 * time.Millisecond)
+		clk.Sleep(100 * time.Millisecond)
 		ch1 <- "来自ch1的消息"
 	}()
 
 	go func() {
-		time.Sleep(200 * time.Millisecond)
+		clk.Sleep(200 * time.Millisecond)
 		ch2 <- "来自ch2的消息"
 	}()
 
@@ -205,34 +315,46 @@ func main() {
 			fmt.Println("收到:", msg1)
 		case msg2 := <-ch2:
 			fmt.Println("收到:", msg2)
-		case <-time.After(300 * time.Millisecond):
+		case <-clk.After(300 * time.Millisecond):
 			fmt.Println("超时")
 		}
 	}
+}
 
-	// 8. 斐波那契示例
-	fmt.Println("\n8. 斐波那契与select:")
-	fibCh := make(chan int)
-	quit := make(chan int)
+// 8. 生成器与组合器
+func demoFibonacci() {
+	// 超时仍然由ctx控制：2秒内没读完生成器也会退出
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
 
-	go func() {
-		for i := 0; i < 10; i++ {
-			fmt.Printf("斐波那契数: %d\n", <-fibCh)
-		}
-		quit <- 0
-	}()
+	// Take 取够10个后自动停止上游的生成器，不再需要quit channel
+	for n := range gen.Take(gen.Fibonacci(ctx), 10).C() {
+		fmt.Printf("斐波那契数: %d\n", n)
+	}
 
-	fibonacci(fibCh, quit)
+	fmt.Println("前10个素数:", gen.Take(gen.Primes(ctx), 10).Slice())
+	fmt.Println("跳过5个后的偶数:", gen.Take(gen.Skip(gen.Range(ctx, 0, 100, 2), 5), 5).Slice())
 
-	// 9. Channel方向（单向channel）
-	fmt.Println("\n9. Channel方向:")
+	// Zip 把两个生成器一一配对
+	for p := range gen.Zip(gen.Range(ctx, 1, 4, 1), gen.Primes(ctx)).C() {
+		fmt.Printf("第%d个素数是 %d\n", p.First, p.Second)
+	}
 
-	// 只写channel
-	sendOnly := make(chan<- int)
-	go func() {
+	// Merge 合并多个生成器，顺序取决于调度，这里只看总数
+	merged := gen.Merge(gen.Range(ctx, 0, 5, 1), gen.Range(ctx, 100, 105, 1))
+	fmt.Println("合并后共", len(merged.Slice()), "个值")
+}
+
+// 9. Channel方向（单向channel）
+func demoChannelDirection() {
+	// 只写channel：创建双向channel，把只写的一端交给发送方
+	// 直接 make(chan<- int) 得到的channel没人能读，发送方会永远阻塞
+	values := make(chan int)
+	go func(sendOnly chan<- int) {
 		sendOnly <- 42
 		close(sendOnly)
-	}()
+	}(values)
+	fmt.Printf("从只写channel的另一端读取: %d\n", <-values)
 
 	// 只读channel（需要从双向channel转换）
 	bothWays := make(chan int, 1)
@@ -240,18 +362,379 @@ func main() {
 
 	readOnly := (<-chan int)(bothWays)
 	fmt.Printf("从只读channel读取: %d\n", <-readOnly)
+}
 
-	// 10. 优雅关闭
-	fmt.Println("\n10. 优雅关闭:")
+// 10. 优雅关闭
+func demoGracefulShutdown(clk clock.Clock) {
 	done := make(chan bool)
 
 	go func() {
 		fmt.Println("工作中...")
-		time.Sleep(1 * time.Second)
+		clk.Sleep(1 * time.Second)
 		fmt.Println("工作完成")
 		done <- true
 	}()
 
 	<-done
-	fmt.Println("程序结束")
+}
+
+// 11. goroutine泄漏检测：故意制造一个泄漏，再让它退出
+func demoLeakCheck() {
+	snap := leakcheck.Take()
+
+	// 和原来的只写channel示例一样：发送到一个没人读的无缓冲channel，goroutine永远阻塞
+	ch := make(chan int)
+	go func() {
+		ch <- 42
+	}()
+
+	if err := snap.Check(100 * time.Millisecond); err != nil {
+		for _, g := range err.(*leakcheck.LeakError).Goroutines {
+			fmt.Println("检测到泄漏:", g)
+		}
+	}
+
+	<-ch // 读走数据，让goroutine退出
+	if err := snap.Check(time.Second); err == nil {
+		fmt.Println("读取之后不再泄漏")
+	}
+}
+
+// 12. 假时钟：同样的任务用假时钟运行，时间由我们手动推进，瞬间完成且顺序确定
+func demoFakeClock() {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	start := time.Now()
+	begin := fake.Now()
+
+	// 每个任务一个WaitGroup，这样推进时间后可以等到恰好那一个任务完成
+	wgs := make([]*sync.WaitGroup, 3)
+	for i := range wgs {
+		wgs[i] = &sync.WaitGroup{}
+		wgs[i].Add(1)
+		go taskWithWaitGroup(fake, i+1, wgs[i])
+	}
+
+	// 先等三个任务都进入Sleep，再推进时间，否则可能在它们开始等待之前就推进了
+	fake.BlockUntil(len(wgs))
+	for _, wg := range wgs {
+		fake.Advance(100 * time.Millisecond) // 任务i需要 i*100ms，每次推进只有一个任务到期
+		wg.Wait()
+	}
+
+	fmt.Printf("假时钟经过了 %v，实际耗时 %v\n", fake.Since(begin), time.Since(start).Round(time.Microsecond))
+}
+
+// 13. 发布/订阅：按主题广播，慢订阅者按各自的策略处理
+func demoPubSub() {
+	ctx := context.Background()
+	bus := pubsub.New[string]()
+
+	all, _ := bus.Subscribe("orders.#", pubsub.Options{Buffer: 10})
+	created, _ := bus.Subscribe("orders.*.created", pubsub.Options{Buffer: 10})
+	// 慢订阅者：缓冲只有2条，满了就丢弃最旧的
+	latest, _ := bus.Subscribe("orders.#", pubsub.Options{Buffer: 2, Policy: pubsub.DropOldest})
+
+	// 每个订阅者在自己的goroutine里读取，读完后汇报
+	report := func(name string, sub *pubsub.Subscription[string], wg *sync.WaitGroup) {
+		defer wg.Done()
+		var got []string
+		for msg := range sub.C() {
+			got = append(got, msg.Payload)
+		}
+		fmt.Printf("%s: 收到 %v，丢弃 %d 条\n", name, got, sub.Dropped())
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go report("全部订单", all, &wg)
+	go report("新建订单", created, &wg)
+
+	events := []struct{ topic, payload string }{
+		{"orders.cn.created", "#1 创建"},
+		{"orders.us.created", "#2 创建"},
+		{"orders.cn.paid", "#1 支付"},
+		{"orders.cn.shipped", "#1 发货"},
+		{"users.signup", "新用户"}, // 没有人订阅
+	}
+	for _, e := range events {
+		n, err := bus.Publish(ctx, e.topic, e.payload)
+		if err != nil {
+			fmt.Println("发布失败:", err)
+			continue
+		}
+		fmt.Printf("发布 %-18s -> %d 个订阅者\n", e.topic, n)
+	}
+
+	// 关闭总线：所有订阅者的channel被关闭，读完剩余消息后退出
+	// latest 在关闭后才开始读，只能读到缓冲里最新的2条
+	bus.Close()
+	wg.Wait()
+	wg.Add(1)
+	report("最新订单", latest, &wg)
+}
+
+// 14. 任务调度：用假时钟驱动调度器，模拟的一个上午瞬间跑完
+func demoScheduler() {
+	h := scheduler.NewHarness(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+	s := h.Scheduler
+	s.OnError = func(name string, err error) {
+		fmt.Printf("  %s 出错: %v\n", name, err)
+	}
+
+	logRun := func(name string) scheduler.Job {
+		return func(ctx context.Context) error {
+			fmt.Printf("[%s] %s\n", h.Now().Format("15:04"), name)
+			return nil
+		}
+	}
+
+	s.After("预热缓存", 10*time.Minute, logRun("预热缓存"))
+	s.Every("心跳", time.Hour, logRun("心跳"), scheduler.Options{})
+	s.Cron("报表", "30 9-11 * * 1-5", logRun("报表"), scheduler.Options{}) // 工作日9-11点的30分
+	s.Cron("备份", "@daily", func(ctx context.Context) error {
+		return errors.New("磁盘已满")
+	}, scheduler.Options{})
+
+	h.Advance(4 * time.Hour)
+	if st, ok := s.Stats("报表"); ok {
+		fmt.Printf("报表: 运行 %d 次，下次 %s\n", st.Runs, st.Next.Format("01-02 15:04"))
+	}
+
+	h.Advance(12 * time.Hour) // 跨过午夜，备份运行并出错
+	if err := s.Stop(context.Background()); err == nil {
+		fmt.Println("调度器已停止，所有任务都已结束")
+	}
+}
+
+// 15. 同步原语：信号量、屏障、门闩、限流器和重复请求合并
+func demoPrimitives(clk clock.Clock) {
+	ctx := context.Background()
+
+	// 带权信号量：容量为3，大任务占2个名额，小任务占1个
+	sem := primitives.NewSemaphore(3)
+	var wg sync.WaitGroup
+	var active, peak atomic.Int64
+	for i, weight := range []int64{2, 1, 1, 2, 1} {
+		// 名额不够时阻塞，直到其他任务归还
+		wg.Add(1)
+		go func(id int, weight int64) {
+			defer wg.Done()
+			if err := sem.Acquire(ctx, weight); err != nil {
+				return
+			}
+			defer sem.Release(weight)
+			cur := active.Add(weight)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			clk.Sleep(10 * time.Millisecond)
+			active.Add(-weight)
+		}(i, weight)
+	}
+	wg.Wait()
+	fmt.Println("信号量: 同时占用的名额最多为", peak.Load())
+
+	// 循环屏障：3个goroutine分两个阶段，每个阶段都等所有人到齐
+	phase := 0
+	barrier := primitives.NewBarrier(3, func() {
+		phase++
+		fmt.Printf("屏障: 第%d阶段全部到齐\n", phase)
+	})
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for step := 0; step < 2; step++ {
+				barrier.Await(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 倒计时门闩：等3个服务都初始化完成
+	ready := primitives.NewLatch(3)
+	for i := 0; i < 3; i++ {
+		go ready.CountDown() // 每个服务初始化完成后减一
+	}
+	if err := ready.Wait(ctx); err == nil {
+		fmt.Println("门闩: 所有服务已就绪")
+	}
+
+	// 令牌桶：假时钟下每秒2个令牌、桶容量3，突发3个之后被限流
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := primitives.NewLimiter(fake, 2, 3)
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if limiter.Allow() {
+			allowed++
+		}
+	}
+	fake.Advance(time.Second)
+	refilled := 0
+	for limiter.Allow() {
+		refilled++
+	}
+	fmt.Printf("限流器: 突发5个请求通过%d个，1秒后补充了%d个令牌\n", allowed, refilled)
+
+	// 重复请求合并：10个并发请求同一个key，只真正查询一次
+	var group primitives.Group[string, string]
+	var queries atomic.Int32
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			group.Do("user:42", func() (string, error) {
+				queries.Add(1)
+				clk.Sleep(20 * time.Millisecond) // 模拟慢查询，让其他请求赶上
+				return "张三", nil
+			})
+		}()
+	}
+	close(start)
+	wg.Wait()
+	fmt.Println("重复请求合并: 10个请求实际查询", queries.Load(), "次")
+}
+
+// 16. Actor模型：用消息代替共享内存，计数器和银行账户都不需要锁
+func demoActors() {
+	sys := actor.NewSystem(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	counter, err := actor.Spawn(sys, "counter", func() actor.Actor[counterMsg] {
+		return &counterActor{}
+	}, actor.Options{Mailbox: 16})
+	if err != nil {
+		fmt.Println("启动actor失败:", err)
+		return
+	}
+
+	// 与Mutex示例一样，3个goroutine各加100次
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := counter.Send(ctx, counterMsg{delta: 1}); err != nil {
+					fmt.Println("发送失败:", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	value, err := actor.Ask(ctx, counter, func(reply chan<- int) counterMsg {
+		return counterMsg{reply: reply}
+	})
+	if err != nil {
+		fmt.Println("查询计数失败:", err)
+	}
+	fmt.Println("计数器actor的值:", value)
+
+	// 账户actor崩溃后由监督策略重启，重启后状态回到初始的开户金额
+	account, err := actor.Spawn(sys, "account", func() actor.Actor[accountMsg] {
+		return &accountActor{balance: 100}
+	}, actor.Options{
+		Mailbox:     16,
+		MaxRestarts: 3,
+		Within:      time.Minute,
+		OnFailure: func(name string, v any, restarted bool) {
+			fmt.Printf("%s 崩溃: %v，重启: %v\n", name, v, restarted)
+		},
+	})
+	if err != nil {
+		fmt.Println("启动actor失败:", err)
+		sys.Shutdown(ctx)
+		return
+	}
+
+	getBalance := func() int {
+		b, err := actor.Ask(ctx, account, func(reply chan<- int) accountMsg { return balance{reply} })
+		if err != nil {
+			fmt.Println("查询余额失败:", err)
+		}
+		return b
+	}
+	tryWithdraw := func(amount int) error {
+		err, askErr := actor.Ask(ctx, account, func(reply chan<- error) accountMsg {
+			return withdraw{amount: amount, reply: reply}
+		})
+		if askErr != nil {
+			return askErr
+		}
+		return err
+	}
+
+	if err := account.Send(ctx, deposit{amount: 50}); err != nil {
+		fmt.Println("存款失败:", err)
+	}
+	fmt.Println("取款30:", tryWithdraw(30))
+	fmt.Println("取款1000:", tryWithdraw(1000))
+	fmt.Println("余额:", getBalance())
+
+	if err := account.Send(ctx, audit{}); err != nil {
+		fmt.Println("发送审计消息失败:", err)
+	}
+	fmt.Println("重启后的余额:", getBalance())
+
+	if err := sys.Shutdown(ctx); err == nil {
+		fmt.Println("所有actor已停止")
+	}
+}
+
+// noClock 把不需要等待的示例适配成 demos 中的函数类型
+func noClock(run func()) func(clock.Clock) {
+	return func(clock.Clock) { run() }
+}
+
+// demos 按顺序运行的所有示例，测试中会逐个检查它们是否泄漏goroutine
+// 需要等待的示例从参数拿到时钟，main传入真实时钟，测试可以传入假时钟
+var demos = []struct {
+	title string
+	run   func(clk clock.Clock)
+}{
+	{"1. 基本goroutine", demoGoroutines},
+	{"2. Channel基础", noClock(demoChannels)},
+	{"3. 生产者-消费者模式", demoProducerConsumer},
+	{"4. 工作池模式", demoWorkerPool},
+	{"5. 使用WaitGroup", demoWaitGroup},
+	{"6. 使用Mutex保护共享资源", noClock(demoMutex)},
+	{"7. Select语句", demoSelect},
+	{"8. 生成器", noClock(demoFibonacci)},
+	{"9. Channel方向", noClock(demoChannelDirection)},
+	{"10. 优雅关闭", demoGracefulShutdown},
+	{"11. goroutine泄漏检测", noClock(demoLeakCheck)},
+	{"12. 假时钟", noClock(demoFakeClock)},
+	{"13. 发布/订阅", noClock(demoPubSub)},
+	{"14. 任务调度", noClock(demoScheduler)},
+	{"15. 同步原语", demoPrimitives},
+	{"16. Actor模型", noClock(demoActors)},
+}
+
+func main() {
+	fmt.Println("=== Go语言并发编程 ===")
+
+	// 每个示例结束后检查它启动的goroutine是否都已退出，有泄漏时以非0状态退出
+	leaked := false
+	for _, d := range demos {
+		fmt.Printf("\n%s:\n", d.title)
+		snap := leakcheck.Take()
+		d.run(clock.Real)
+		if err := snap.Check(time.Second); err != nil {
+			fmt.Printf("警告: %s 泄漏了goroutine\n%v\n", d.title, err)
+			leaked = true
+		}
+	}
+
+	fmt.Println("\n程序结束")
+	if leaked {
+		os.Exit(1)
+	}
 }
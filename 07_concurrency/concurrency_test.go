@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+	"go-learn/07_concurrency/leakcheck"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// autoAdvance 在后台不断把假时钟推进到下一个到期时间，示例中的等待都会立即结束
+// 测试结束时停止推进，并在泄漏检查之前退出
+func autoAdvance(t *testing.T, fake *clock.Fake) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(100 * time.Microsecond): // 给被唤醒的goroutine时间进入下一次等待
+			}
+			if d, ok := fake.NextDeadline(); ok {
+				fake.Advance(d.Sub(fake.Now()))
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+}
+
+// TestDemosDoNotLeak 逐个运行示例，任何一个示例结束后还有它启动的goroutine在运行就失败
+func TestDemosDoNotLeak(t *testing.T) {
+	for _, d := range demos {
+		t.Run(d.title, func(t *testing.T) {
+			leakcheck.Verify(t)
+			fake := clock.NewFake(epoch)
+			autoAdvance(t, fake)
+			d.run(fake)
+		})
+	}
+}
+
+// start 在后台用假时钟运行示例，返回的channel在示例结束时关闭
+func start(fake *clock.Fake, run func(clock.Clock)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(fake)
+	}()
+	return done
+}
+
+// finish 等待示例结束；假时钟已经推进完，示例应该立即结束
+func finish(t *testing.T, fake *clock.Fake, done <-chan struct{}, want time.Duration) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("示例没有结束，还有 %d 个等待者", fake.Waiters())
+	}
+	if got := fake.Since(epoch); got != want {
+		t.Errorf("假时钟经过了 %v，期望 %v", got, want)
+	}
+}
+
+func TestGoroutinesFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoGoroutines)
+
+	// 两个goroutine各打印3次，每次之后都Sleep 100ms
+	for i := 0; i < 3; i++ {
+		fake.BlockUntil(2)
+		fake.Advance(100 * time.Millisecond)
+	}
+	finish(t, fake, done, 300*time.Millisecond)
+}
+
+func TestProducerConsumerFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoProducerConsumer)
+
+	// 生产者每200ms生产一个，消费者每个处理150ms，消费者总是先处理完并等待下一个
+	for i := 0; i < 5; i++ {
+		fake.BlockUntil(2) // 生产者和消费者都在Sleep
+		fake.Advance(150 * time.Millisecond)
+		fake.Advance(50 * time.Millisecond)
+	}
+	finish(t, fake, done, time.Second)
+}
+
+func TestWorkerPoolFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoWorkerPool)
+
+	// 3个worker先处理job 1-3，job 4立即失败，job 5在第二轮处理
+	fake.BlockUntil(3)
+	fake.Advance(500 * time.Millisecond)
+	fake.BlockUntil(1)
+	fake.Advance(500 * time.Millisecond)
+	finish(t, fake, done, time.Second)
+}
+
+func TestWaitGroupFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoWaitGroup)
+
+	fake.BlockUntil(3)
+	fake.Advance(300 * time.Millisecond) // 最慢的任务3需要300ms
+	finish(t, fake, done, 300*time.Millisecond)
+}
+
+func TestSelectFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoSelect)
+
+	// 两个发送方的Sleep和第一次select的超时
+	fake.BlockUntil(3)
+	fake.Advance(100 * time.Millisecond)
+	fake.Advance(100 * time.Millisecond) // ch2在超时之前到达，两次select都没有超时
+	finish(t, fake, done, 200*time.Millisecond)
+}
+
+func TestGracefulShutdownFakeClock(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	done := start(fake, demoGracefulShutdown)
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	finish(t, fake, done, time.Second)
+}
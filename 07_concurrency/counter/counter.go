@@ -0,0 +1,147 @@
+// Package counter 提供几种并发安全计数器的实现，便于比较它们在竞争下的表现
+package counter
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter 并发安全的计数器
+type Counter interface {
+	Add(delta int64)
+	Increment()
+	Value() int64
+}
+
+// MutexCounter 用一把互斥锁保护计数，所有goroutine争用同一把锁
+type MutexCounter struct {
+	mu    sync.Mutex
+	value int64
+}
+
+func (c *MutexCounter) Add(delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += delta
+}
+
+func (c *MutexCounter) Increment() { c.Add(1) }
+
+func (c *MutexCounter) Value() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// AtomicCounter 用原子操作计数，无锁，但所有CPU仍然争用同一个缓存行
+type AtomicCounter struct {
+	value atomic.Int64
+}
+
+func (c *AtomicCounter) Add(delta int64) { c.value.Add(delta) }
+func (c *AtomicCounter) Increment()      { c.value.Add(1) }
+func (c *AtomicCounter) Value() int64    { return c.value.Load() }
+
+// shard 独占一个缓存行（64字节），避免相邻分片之间的伪共享
+type shard struct {
+	value atomic.Int64
+	_     [56]byte
+}
+
+// ShardedCounter 把计数分散到多个分片，写入时随机选一个分片，读取时求和
+// 写多读少时竞争最小；Value 不是某一时刻的精确快照
+type ShardedCounter struct {
+	shards []shard
+	mask   int
+}
+
+// NewSharded 创建分片计数器，分片数为不小于GOMAXPROCS的2的幂
+func NewSharded() *ShardedCounter {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return &ShardedCounter{shards: make([]shard, n), mask: n - 1}
+}
+
+// Go没有公开当前CPU编号，这里用随机数近似"每个CPU一个分片"
+func (c *ShardedCounter) Add(delta int64) {
+	c.shards[rand.Int()&c.mask].value.Add(delta)
+}
+
+func (c *ShardedCounter) Increment() { c.Add(1) }
+
+func (c *ShardedCounter) Value() int64 {
+	var total int64
+	for i := range c.shards {
+		total += c.shards[i].value.Load()
+	}
+	return total
+}
+
+// Registry 按名称管理的一组计数器
+type Registry struct {
+	mu       sync.RWMutex
+	counters map[string]Counter
+	factory  func() Counter
+}
+
+// NewRegistry 创建计数器注册表，factory为nil时使用 AtomicCounter
+func NewRegistry(factory func() Counter) *Registry {
+	if factory == nil {
+		factory = func() Counter { return &AtomicCounter{} }
+	}
+	return &Registry{counters: make(map[string]Counter), factory: factory}
+}
+
+// Get 返回名为name的计数器，不存在时创建
+func (r *Registry) Get(name string) Counter {
+	r.mu.RLock()
+	c, ok := r.counters[name]
+	r.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 加写锁前可能已被其他goroutine创建
+	if c, ok := r.counters[name]; ok {
+		return c
+	}
+	c = r.factory()
+	r.counters[name] = c
+	return c
+}
+
+// Add 给名为name的计数器加上delta
+func (r *Registry) Add(name string, delta int64) {
+	r.Get(name).Add(delta)
+}
+
+// Names 返回所有计数器名称，按字典序排列
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Snapshot 返回所有计数器当前的值
+// 各计数器分别读取，并发写入时不同计数器的值可能不属于同一时刻
+func (r *Registry) Snapshot() map[string]int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := make(map[string]int64, len(r.counters))
+	for name, c := range r.counters {
+		snap[name] = c.Value()
+	}
+	return snap
+}
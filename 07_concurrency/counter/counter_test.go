@@ -0,0 +1,98 @@
+package counter
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// implementations 参与测试和基准测试的计数器
+var implementations = []struct {
+	name string
+	new  func() Counter
+}{
+	{"mutex", func() Counter { return &MutexCounter{} }},
+	{"atomic", func() Counter { return &AtomicCounter{} }},
+	{"sharded", func() Counter { return NewSharded() }},
+	{"registry", func() Counter { return registryCounter{NewRegistry(nil)} }},
+}
+
+// registryCounter 每次都按名称查找同一个计数器，衡量注册表查找的额外开销
+type registryCounter struct{ r *Registry }
+
+func (c registryCounter) Add(delta int64) { c.r.Add("requests", delta) }
+func (c registryCounter) Increment()      { c.r.Add("requests", 1) }
+func (c registryCounter) Value() int64    { return c.r.Get("requests").Value() }
+
+func TestConcurrentIncrement(t *testing.T) {
+	const goroutines, perGoroutine = 8, 1000
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			c := impl.new()
+			var wg sync.WaitGroup
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < perGoroutine; j++ {
+						c.Increment()
+					}
+					c.Add(-1)
+				}()
+			}
+			wg.Wait()
+			if want := int64(goroutines * (perGoroutine - 1)); c.Value() != want {
+				t.Errorf("Value() = %d，期望 %d", c.Value(), want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(func() Counter { return &AtomicCounter{} })
+	if r.Get("a") != r.Get("a") {
+		t.Error("同名计数器应为同一个")
+	}
+	r.Add("b", 2)
+	r.Add("a", 1)
+	r.Add("b", 3)
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(r.Names(), want) {
+		t.Errorf("Names() = %v，期望 %v", r.Names(), want)
+	}
+	if want := map[string]int64{"a": 1, "b": 5}; !reflect.DeepEqual(r.Snapshot(), want) {
+		t.Errorf("Snapshot() = %v，期望 %v", r.Snapshot(), want)
+	}
+}
+
+// BenchmarkIncrement 比较各实现在多goroutine竞争下的性能，用 -cpu 改变竞争程度：
+//
+//	go test -bench Increment -cpu 1,2,4,8 ./07_concurrency/counter
+func BenchmarkIncrement(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			c := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Increment()
+				}
+			})
+			if c.Value() != int64(b.N) {
+				b.Fatalf("Value() = %d，期望 %d", c.Value(), b.N)
+			}
+		})
+	}
+}
+
+// BenchmarkValue 读取的开销：分片计数器需要累加所有分片
+func BenchmarkValue(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			c := impl.new()
+			c.Add(42)
+			for i := 0; i < b.N; i++ {
+				c.Value()
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package gen 基于goroutine和channel的可取消生成器
+//
+// 生成器在自己的goroutine里产出值，使用者读取 C() 或调用 Next。
+// 不再需要时调用 Stop（或取消创建时的ctx），生成器的goroutine就会退出；
+// Take、Zip 等组合器结束时会自动停止上游，不会留下阻塞的goroutine。
+package gen
+
+import "context"
+
+// Gen 一个生成器
+type Gen[T any] struct {
+	c    <-chan T
+	stop context.CancelFunc
+}
+
+// New 创建生成器：produce在新的goroutine中运行，通过yield产出值，
+// yield返回false表示生成器已被停止，produce应当尽快返回
+func New[T any](ctx context.Context, produce func(ctx context.Context, yield func(T) bool)) *Gen[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan T)
+
+	go func() {
+		defer close(c)
+		defer cancel()
+		produce(ctx, func(v T) bool {
+			select {
+			case c <- v:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return &Gen[T]{c: c, stop: cancel}
+}
+
+// C 返回产出值的channel，生成器结束或被停止后关闭
+func (g *Gen[T]) C() <-chan T { return g.c }
+
+// Stop 停止生成器，可以重复调用
+func (g *Gen[T]) Stop() { g.stop() }
+
+// Next 读取下一个值，生成器结束时ok为false
+func (g *Gen[T]) Next() (v T, ok bool) {
+	v, ok = <-g.c
+	return v, ok
+}
+
+// Slice 读取所有剩余的值，只能用于有限的生成器（或先用 Take 截断）
+func (g *Gen[T]) Slice() []T {
+	var items []T
+	for v := range g.c {
+		items = append(items, v)
+	}
+	return items
+}
+
+// 组合器基于 context.Background 创建：上游被取消时会关闭channel，组合器随之结束，
+// 组合器被 Stop 时再通过 defer 停止上游
+
+// recv 读取g的下一个值，ctx取消时立即返回false
+// 组合器都通过它读取上游，被 Stop 时不会一直等着迟迟不产出的上游
+func recv[T any](ctx context.Context, g *Gen[T]) (v T, ok bool) {
+	select {
+	case v, ok = <-g.c:
+		return v, ok
+	case <-ctx.Done():
+		return v, false
+	}
+}
+
+// Take 只取前n个值，取完后停止上游
+func Take[T any](g *Gen[T], n int) *Gen[T] {
+	return New(context.Background(), func(ctx context.Context, yield func(T) bool) {
+		defer g.Stop()
+		for i := 0; i < n; i++ {
+			v, ok := recv(ctx, g)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	})
+}
+
+// Skip 跳过前n个值
+func Skip[T any](g *Gen[T], n int) *Gen[T] {
+	return New(context.Background(), func(ctx context.Context, yield func(T) bool) {
+		defer g.Stop()
+		for i := 0; i < n; i++ {
+			if _, ok := recv(ctx, g); !ok {
+				return
+			}
+		}
+		for {
+			v, ok := recv(ctx, g)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	})
+}
+
+// Filter 只保留keep返回true的值
+func Filter[T any](g *Gen[T], keep func(T) bool) *Gen[T] {
+	return New(context.Background(), func(ctx context.Context, yield func(T) bool) {
+		defer g.Stop()
+		for {
+			v, ok := recv(ctx, g)
+			if !ok || (keep(v) && !yield(v)) {
+				return
+			}
+		}
+	})
+}
+
+// Pair Zip 产出的一对值
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip 把两个生成器的值一一配对，任意一个结束时停止
+func Zip[A, B any](a *Gen[A], b *Gen[B]) *Gen[Pair[A, B]] {
+	return New(context.Background(), func(ctx context.Context, yield func(Pair[A, B]) bool) {
+		defer a.Stop()
+		defer b.Stop()
+		for {
+			x, ok := recv(ctx, a)
+			if !ok {
+				return
+			}
+			y, ok := recv(ctx, b)
+			if !ok || !yield(Pair[A, B]{x, y}) {
+				return
+			}
+		}
+	})
+}
+
+// Merge 合并多个生成器，值按到达顺序产出，全部结束后才结束
+func Merge[T any](gens ...*Gen[T]) *Gen[T] {
+	return New(context.Background(), func(ctx context.Context, yield func(T) bool) {
+		for _, g := range gens {
+			defer g.Stop()
+		}
+
+		// 每个输入一个转发goroutine，转发到out；produce返回前先停止输入，再等转发goroutine退出
+		out := make(chan T)
+		done := make(chan struct{})
+		for _, g := range gens {
+			go func(g *Gen[T]) {
+				defer func() { done <- struct{}{} }()
+				for {
+					v, ok := recv(ctx, g)
+					if !ok {
+						return
+					}
+					select {
+					case out <- v:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(g)
+		}
+
+		// Merge被停止时ctx被取消，转发goroutine随之退出，等它们都退出后再返回
+		remaining := len(gens)
+		defer func() {
+			for ; remaining > 0; remaining-- {
+				<-done
+			}
+		}()
+		for remaining > 0 {
+			select {
+			case v := <-out:
+				if !yield(v) {
+					return
+				}
+			case <-done:
+				remaining--
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+func TestSources(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Range", Range(ctx, 0, 5, 1).Slice(), []int{0, 1, 2, 3, 4}},
+		{"Range负步长", Range(ctx, 5, 0, -2).Slice(), []int{5, 3, 1}},
+		{"Range空", Range(ctx, 5, 0, 1).Slice(), nil},
+		{"Range步长0", Take(Range(ctx, 7, 0, 0), 3).Slice(), []int{7, 7, 7}},
+		{"Fibonacci", Take(Fibonacci(ctx), 8).Slice(), []int{0, 1, 1, 2, 3, 5, 8, 13}},
+		{"Primes", Take(Primes(ctx), 8).Slice(), []int{2, 3, 5, 7, 11, 13, 17, 19}},
+	}
+	for _, tt := range tests {
+		if fmt.Sprint(tt.got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %v，期望 %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	even := func(n int) bool { return n%2 == 0 }
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Take多于剩余", Take(Range(ctx, 0, 3, 1), 10).Slice(), []int{0, 1, 2}},
+		{"Take 0", Take(Primes(ctx), 0).Slice(), nil},
+		{"Skip", Take(Skip(Fibonacci(ctx), 5), 3).Slice(), []int{5, 8, 13}},
+		{"Skip全部", Skip(Range(ctx, 0, 3, 1), 5).Slice(), nil},
+		{"Filter", Take(Filter(Fibonacci(ctx), even), 4).Slice(), []int{0, 2, 8, 34}},
+	}
+	for _, tt := range tests {
+		if fmt.Sprint(tt.got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %v，期望 %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	pairs := Zip(Range(ctx, 1, 4, 1), Primes(ctx)).Slice()
+	if got := fmt.Sprint(pairs); got != "[{1 2} {2 3} {3 5}]" {
+		t.Errorf("Zip = %s", got)
+	}
+
+	merged := Merge(Range(ctx, 0, 3, 1), Range(ctx, 10, 13, 1), Range(ctx, 20, 20, 1)).Slice()
+	sort.Ints(merged)
+	if got := fmt.Sprint(merged); got != "[0 1 2 10 11 12]" {
+		t.Errorf("Merge = %s", got)
+	}
+}
+
+func TestNext(t *testing.T) {
+	leakcheck.Verify(t)
+	g := Range(context.Background(), 0, 2, 1)
+	for want := 0; want < 2; want++ {
+		if v, ok := g.Next(); !ok || v != want {
+			t.Fatalf("Next = %d, %v，期望 %d", v, ok, want)
+		}
+	}
+	if _, ok := g.Next(); ok {
+		t.Error("生成器结束后 Next 应返回false")
+	}
+}
+
+// silent 一个在被停止之前永远不产出值的生成器
+func silent(ctx context.Context) *Gen[int] {
+	return New(ctx, func(ctx context.Context, yield func(int) bool) {
+		<-ctx.Done()
+	})
+}
+
+// waitClosed 等待生成器的channel关闭，超时则失败，而不是让测试一直卡住
+func waitClosed[T any](t *testing.T, g *Gen[T]) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range g.C() {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop 之后生成器没有结束")
+	}
+}
+
+// TestStopWhileWaitingForUpstream 组合器在等待上游时被 Stop，自己和上游都应退出
+func TestStopWhileWaitingForUpstream(t *testing.T) {
+	combinators := map[string]func(ctx context.Context) *Gen[int]{
+		"Take":   func(ctx context.Context) *Gen[int] { return Take(silent(ctx), 5) },
+		"Skip":   func(ctx context.Context) *Gen[int] { return Skip(silent(ctx), 1) },
+		"Filter": func(ctx context.Context) *Gen[int] { return Filter(silent(ctx), func(int) bool { return true }) },
+		"Filter全部丢弃": func(ctx context.Context) *Gen[int] {
+			return Filter(Range(ctx, 0, 0, 0), func(int) bool { return false })
+		},
+		"Merge": func(ctx context.Context) *Gen[int] { return Merge(silent(ctx), silent(ctx)) },
+	}
+	for name, build := range combinators {
+		t.Run(name, func(t *testing.T) {
+			leakcheck.Verify(t)
+			g := build(context.Background())
+			g.Stop()
+			waitClosed(t, g)
+		})
+	}
+
+	t.Run("Zip", func(t *testing.T) {
+		leakcheck.Verify(t)
+		z := Zip(Range(context.Background(), 0, 10, 1), silent(context.Background()))
+		z.Stop()
+		waitClosed(t, z)
+	})
+}
+
+// TestStopAfterPartialRead 读了一部分之后 Stop，上游的无限生成器也应退出
+func TestStopAfterPartialRead(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	gens := []*Gen[int]{
+		Skip(Fibonacci(ctx), 2),
+		Filter(Primes(ctx), func(n int) bool { return n%4 == 1 }),
+		Merge(Fibonacci(ctx), Primes(ctx)),
+	}
+	for _, g := range gens {
+		g.Next()
+		g.Next()
+		g.Stop()
+	}
+}
+
+func TestCancelContext(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	g := Filter(Skip(Primes(ctx), 3), func(int) bool { return true })
+	g.Next()
+	cancel()
+	waitClosed(t, g)
+}
@@ -0,0 +1,54 @@
+package gen
+
+import "context"
+
+// Range 产出 start, start+step, ... 直到越过end（不含end）
+// step为0时产出无限个start
+func Range(ctx context.Context, start, end, step int) *Gen[int] {
+	return New(ctx, func(ctx context.Context, yield func(int) bool) {
+		for i := start; step == 0 || (step > 0 && i < end) || (step < 0 && i > end); i += step {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+}
+
+// Fibonacci 产出无限的斐波那契数列 0, 1, 1, 2, 3, 5, ...
+func Fibonacci(ctx context.Context) *Gen[int] {
+	return New(ctx, func(ctx context.Context, yield func(int) bool) {
+		x, y := 0, 1
+		for yield(x) {
+			x, y = y, x+y
+		}
+	})
+}
+
+// Primes 产出无限的素数序列，用试除法检查每个奇数
+func Primes(ctx context.Context) *Gen[int] {
+	return New(ctx, func(ctx context.Context, yield func(int) bool) {
+		if !yield(2) {
+			return
+		}
+		var primes []int
+		for n := 3; ; n += 2 {
+			isPrime := true
+			for _, p := range primes {
+				if p*p > n {
+					break
+				}
+				if n%p == 0 {
+					isPrime = false
+					break
+				}
+			}
+			if !isPrime {
+				continue
+			}
+			primes = append(primes, n)
+			if !yield(n) {
+				return
+			}
+		}
+	})
+}
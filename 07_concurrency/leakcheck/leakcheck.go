@@ -0,0 +1,182 @@
+// Package leakcheck 通过比较前后两次goroutine快照来发现泄漏的goroutine
+//
+// 用法：
+//
+//	snap := leakcheck.Take()
+//	runSomething()
+//	if err := snap.Check(time.Second); err != nil {
+//		fmt.Println(err) // 列出多出来的goroutine及其调用栈
+//	}
+//
+// 在测试中可以用 leakcheck.Verify(t) 在测试结束时自动检查。
+package leakcheck
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goroutine 一个goroutine的信息
+type Goroutine struct {
+	ID    int64
+	State string // 如 running、chan send、select
+	Top   string // 栈顶函数
+	Stack string // 完整调用栈
+}
+
+func (g Goroutine) String() string {
+	return fmt.Sprintf("goroutine %d [%s] %s", g.ID, g.State, g.Top)
+}
+
+// Snapshot 某一时刻所有goroutine的编号
+type Snapshot struct {
+	ids map[int64]bool
+}
+
+// Take 记录当前所有goroutine
+func Take() Snapshot {
+	s := Snapshot{ids: make(map[int64]bool)}
+	for _, g := range Current() {
+		s.ids[g.ID] = true
+	}
+	return s
+}
+
+// Current 返回当前所有goroutine（不含调用者自己和运行时内部的goroutine）
+func Current() []Goroutine {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var gs []Goroutine
+	// 第一段是调用者自己的栈
+	for i, block := range bytes.Split(buf, []byte("\n\n")) {
+		if i == 0 {
+			continue
+		}
+		if g, ok := parse(string(block)); ok && !ignored(g) {
+			gs = append(gs, g)
+		}
+	}
+	return gs
+}
+
+// parse 解析 runtime.Stack 输出中的一段：
+//
+//	goroutine 18 [chan send]:
+//	main.main.func1()
+//		/path/main.go:12 +0x2c
+func parse(block string) (Goroutine, bool) {
+	header, rest, _ := strings.Cut(block, "\n")
+	fields := strings.SplitN(strings.TrimPrefix(header, "goroutine "), " ", 2)
+	if len(fields) != 2 {
+		return Goroutine{}, false
+	}
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return Goroutine{}, false
+	}
+
+	state := strings.TrimSuffix(strings.TrimPrefix(fields[1], "["), "]:")
+	state, _, _ = strings.Cut(state, ",") // 去掉 ", 2 minutes" 之类的等待时长
+	top, _, _ := strings.Cut(rest, "\n")
+	if i := strings.LastIndex(top, "("); i > 0 {
+		top = top[:i]
+	}
+	return Goroutine{ID: id, State: state, Top: top, Stack: block}, true
+}
+
+// ignored 判断是否为运行时或测试框架自带的goroutine
+// 普通goroutine的调用栈中不显示运行时内部的帧，栈顶仍是runtime的只有运行时自己的goroutine
+func ignored(g Goroutine) bool {
+	for _, prefix := range []string{"runtime.", "testing.", "os/signal."} {
+		if strings.HasPrefix(g.Top, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Leaked 返回快照之后新出现、目前仍在运行的goroutine
+func (s Snapshot) Leaked() []Goroutine {
+	var leaked []Goroutine
+	for _, g := range Current() {
+		if !s.ids[g.ID] {
+			leaked = append(leaked, g)
+		}
+	}
+	sort.Slice(leaked, func(i, j int) bool { return leaked[i].ID < leaked[j].ID })
+	return leaked
+}
+
+// LeakError 检查到泄漏时返回的错误
+type LeakError struct {
+	Goroutines []Goroutine
+}
+
+func (e *LeakError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "发现 %d 个泄漏的goroutine:", len(e.Goroutines))
+	for _, g := range e.Goroutines {
+		b.WriteString("\n\n")
+		b.WriteString(g.Stack)
+	}
+	return b.String()
+}
+
+// Check 在timeout内反复检查，直到快照之后启动的goroutine全部退出
+// 正在收尾的goroutine需要一点时间退出，所以不能只检查一次
+func (s Snapshot) Check(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	wait := time.Millisecond
+	for {
+		leaked := s.Leaked()
+		if len(leaked) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return &LeakError{Goroutines: leaked}
+		}
+		time.Sleep(wait)
+		if wait < 100*time.Millisecond {
+			wait *= 2
+		}
+	}
+}
+
+// TB 是 testing.TB 中用到的部分，避免本包依赖testing
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// DefaultTimeout Verify 等待goroutine退出的时间
+var DefaultTimeout = time.Second
+
+// Verify 在测试开始时调用，测试结束时检查是否有goroutine泄漏
+//
+//	func TestX(t *testing.T) {
+//		leakcheck.Verify(t)
+//		...
+//	}
+func Verify(t TB) {
+	t.Helper()
+	snap := Take()
+	t.Cleanup(func() {
+		if err := snap.Check(DefaultTimeout); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package leakcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	block := "goroutine 18 [chan send, 2 minutes]:\n" +
+		"main.main.func1()\n" +
+		"\t/path/main.go:12 +0x2c\n" +
+		"created by main.main in goroutine 1\n" +
+		"\t/path/main.go:11 +0x3c"
+	g, ok := parse(block)
+	if !ok {
+		t.Fatal("解析失败")
+	}
+	if g.ID != 18 || g.State != "chan send" || g.Top != "main.main.func1" {
+		t.Errorf("解析结果 = %+v", g)
+	}
+	if _, ok := parse("不是goroutine"); ok {
+		t.Error("非法输入应解析失败")
+	}
+}
+
+func TestCheckFindsLeak(t *testing.T) {
+	snap := Take()
+	block := make(chan struct{})
+	go func() { <-block }()
+
+	err := snap.Check(50 * time.Millisecond)
+	var le *LeakError
+	if !errors.As(err, &le) || len(le.Goroutines) != 1 {
+		t.Fatalf("Check 返回 %v，期望1个泄漏", err)
+	}
+	if g := le.Goroutines[0]; !strings.Contains(g.Top, "TestCheckFindsLeak") || g.State != "chan receive" {
+		t.Errorf("泄漏的goroutine = %+v", g)
+	}
+
+	close(block)
+	if err := snap.Check(time.Second); err != nil {
+		t.Errorf("goroutine退出后 Check 返回 %v", err)
+	}
+}
+
+// Check 要等待正在收尾的goroutine，而不是只看一次
+func TestCheckWaitsForExit(t *testing.T) {
+	snap := Take()
+	go time.Sleep(30 * time.Millisecond)
+	if err := snap.Check(time.Second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIgnoresExistingGoroutines(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+
+	snap := Take()
+	if err := snap.Check(10 * time.Millisecond); err != nil {
+		t.Errorf("快照之前启动的goroutine不算泄漏: %v", err)
+	}
+}
+
+// fakeTB 记录 Verify 的报告，代替真实的 *testing.T
+type fakeTB struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (f *fakeTB) Helper() {}
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestVerify(t *testing.T) {
+	old := DefaultTimeout
+	DefaultTimeout = 50 * time.Millisecond
+	defer func() { DefaultTimeout = old }()
+
+	clean := &fakeTB{}
+	Verify(clean)
+	clean.finish()
+	if len(clean.errors) != 0 {
+		t.Errorf("没有泄漏时报告了 %v", clean.errors)
+	}
+
+	leaky := &fakeTB{}
+	Verify(leaky)
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+	leaky.finish()
+	if len(leaky.errors) != 1 {
+		t.Errorf("有泄漏时报告了 %d 次，期望 1", len(leaky.errors))
+	}
+}
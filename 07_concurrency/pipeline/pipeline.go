@@ -0,0 +1,249 @@
+// Package pipeline 提供基于channel的可组合泛型流水线阶段
+//
+// 约定：每个阶段启动自己的goroutine，并在输入channel关闭或ctx取消时关闭输出channel，
+// 因此只要关闭源头或取消ctx，整条流水线的goroutine都会退出。
+// ctx取消后，阶段不再保证把已读取的数据送出。
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// send 把v送入out，ctx取消时返回false
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// recv 从in读取一个值，in关闭或ctx取消时ok为false
+func recv[T any](ctx context.Context, in <-chan T) (v T, ok bool) {
+	select {
+	case v, ok = <-in:
+		return v, ok
+	case <-ctx.Done():
+		return v, false
+	}
+}
+
+// From 把切片变成流水线的源头
+func From[T any](ctx context.Context, items ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range items {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Map 对每个值调用fn
+func Map[In, Out any](ctx context.Context, in <-chan In, fn func(In) Out) <-chan Out {
+	out := make(chan Out)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, fn(v)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Filter 只保留keep返回true的值
+func Filter[T any](ctx context.Context, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if keep(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Batch 把值按size个一组打包；maxWait大于0时，一组等待超过maxWait也会提前送出
+// 输入关闭时送出最后一个不满的批次
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size <= 0 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+
+		var batch []T
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut 启动n个输出，它们竞争读取同一个输入，每个值只会被其中一个输出拿到
+// 适合把耗时的下游阶段并行化，之后再用 FanIn 合并
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				v, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// FanIn 把多个输入合并为一个输出，所有输入都关闭后输出才关闭
+func FanIn[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, in := range ins {
+		wg.Add(1)
+		go func(in <-chan T) {
+			defer wg.Done()
+			for {
+				v, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee 把每个值复制到两个输出，两个输出都要有人读取，否则会互相拖住
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			// 两个输出都送到后才读取下一个值，送出一个后把它置nil避免重复发送
+			o1, o2 := out1, out2
+			for o1 != nil || o2 != nil {
+				select {
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Throttle 限制输出速率，相邻两个值至少间隔interval
+func Throttle[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+
+		var last time.Time
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if wait := time.Until(last.Add(interval)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+			if !send(ctx, out, v) {
+				return
+			}
+			last = time.Now()
+		}
+	}()
+	return out
+}
+
+// Collect 读取in直到关闭或ctx取消，返回读到的所有值
+func Collect[T any](ctx context.Context, in <-chan T) []T {
+	var items []T
+	for {
+		v, ok := recv(ctx, in)
+		if !ok {
+			return items
+		}
+		items = append(items, v)
+	}
+}
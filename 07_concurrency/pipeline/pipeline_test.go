@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+func ints(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// naturals 不会自己结束的源头，只能通过取消ctx停止
+func naturals(ctx context.Context) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 0; ; i++ {
+			if !send(ctx, out, i) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func TestMapFilter(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	even := Filter(ctx, From(ctx, ints(10)...), func(n int) bool { return n%2 == 0 })
+	got := Collect(ctx, Map(ctx, even, func(n int) int { return n * n }))
+	if want := []int{0, 4, 16, 36, 64}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v，期望 %v", got, want)
+	}
+}
+
+func TestFanOutFanIn(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	outs := FanOut(ctx, From(ctx, ints(100)...), 4)
+	for i, out := range outs {
+		outs[i] = Map(ctx, out, func(n int) int { return n * 2 })
+	}
+	got := Collect(ctx, FanIn(ctx, outs...))
+	sort.Ints(got)
+	for i, v := range got {
+		if v != i*2 {
+			t.Fatalf("第%d个值为 %d，期望 %d", i, v, i*2)
+		}
+	}
+	if len(got) != 100 {
+		t.Errorf("得到 %d 个值，期望 100", len(got))
+	}
+}
+
+func TestBatch(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	got := Collect(ctx, Batch(ctx, From(ctx, ints(7)...), 3, 0))
+	if want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v，期望 %v", got, want)
+	}
+}
+
+func TestBatchMaxWait(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	in := make(chan int)
+	out := Batch(ctx, in, 10, 20*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	select {
+	case b := <-out:
+		if !reflect.DeepEqual(b, []int{1, 2}) {
+			t.Errorf("超时送出的批次为 %v", b)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("超过maxWait后没有送出不满的批次")
+	}
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("输入关闭后输出应当关闭")
+	}
+}
+
+func TestTee(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	a, b := Tee(ctx, From(ctx, ints(5)...))
+	gotB := make(chan []int)
+	go func() { gotB <- Collect(ctx, b) }()
+	gotA := Collect(ctx, a)
+	if want := ints(5); !reflect.DeepEqual(gotA, want) || !reflect.DeepEqual(<-gotB, want) {
+		t.Errorf("两个输出应都得到 %v", want)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx := context.Background()
+	start := time.Now()
+	got := Collect(ctx, Throttle(ctx, From(ctx, ints(5)...), 20*time.Millisecond))
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("5个值间隔20ms，只用了 %v", elapsed)
+	}
+	if !reflect.DeepEqual(got, ints(5)) {
+		t.Errorf("got %v", got)
+	}
+}
+
+// 消费者读了几个值后提前退出（取消ctx），所有阶段的goroutine都必须退出
+func TestEarlyConsumerExit(t *testing.T) {
+	stages := map[string]func(ctx context.Context, src <-chan int) <-chan int{
+		"Map": func(ctx context.Context, src <-chan int) <-chan int {
+			return Map(ctx, src, func(n int) int { return n + 1 })
+		},
+		"Filter": func(ctx context.Context, src <-chan int) <-chan int {
+			return Filter(ctx, src, func(n int) bool { return n%3 == 0 })
+		},
+		"FanOut+FanIn": func(ctx context.Context, src <-chan int) <-chan int {
+			return FanIn(ctx, FanOut(ctx, src, 3)...)
+		},
+		"Batch": func(ctx context.Context, src <-chan int) <-chan int {
+			return Map(ctx, Batch(ctx, src, 4, time.Millisecond), func(b []int) int { return len(b) })
+		},
+		"Tee": func(ctx context.Context, src <-chan int) <-chan int {
+			a, b := Tee(ctx, src)
+			go Collect(ctx, b)
+			return a
+		},
+		"Throttle": func(ctx context.Context, src <-chan int) <-chan int { return Throttle(ctx, src, time.Millisecond) },
+	}
+	for name, stage := range stages {
+		t.Run(name, func(t *testing.T) {
+			leakcheck.Verify(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			out := stage(ctx, naturals(ctx))
+			for i := 0; i < 3; i++ {
+				if _, ok := <-out; !ok {
+					t.Fatal("输出过早关闭")
+				}
+			}
+			// 不再读取输出，直接取消
+			cancel()
+		})
+	}
+}
+
+// 取消ctx时，阻塞在读取一个永不关闭、也不再产生数据的输入上的阶段也必须退出
+func TestCancelWhileWaitingForInput(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	idle := make(chan int) // 永远不发送也不关闭
+
+	a, b := Tee(ctx, idle)
+	outs := []<-chan int{
+		Map(ctx, idle, func(n int) int { return n }),
+		Filter(ctx, idle, func(int) bool { return true }),
+		Throttle(ctx, idle, time.Second),
+		FanIn(ctx, FanOut(ctx, idle, 2)...),
+		a, b,
+	}
+	batches := Batch(ctx, idle, 2, time.Second)
+
+	cancel()
+	for _, out := range outs {
+		if _, ok := <-out; ok {
+			t.Error("取消后输出应当关闭且没有数据")
+		}
+	}
+	<-batches
+}
+
+// 消费者用 Collect 配合超时ctx读取无限流，超时后返回已读到的部分
+func TestCollectStopsOnCancel(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	got := Collect(ctx, Throttle(ctx, naturals(ctx), 5*time.Millisecond))
+	if len(got) == 0 || !reflect.DeepEqual(got, ints(len(got))) {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,275 @@
+// Package pool 提供泛型工作池：固定数量的worker并发处理任务，
+// 支持context取消、错误收集、任务panic恢复，以及按提交顺序或完成顺序输出结果
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+// ErrClosed 在 Shutdown 之后继续提交任务时返回
+var ErrClosed = errors.New("工作池已关闭")
+
+// Task 处理单个输入的函数
+type Task[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Options 工作池配置，零值可用
+type Options struct {
+	Workers   int  // worker数量，<=0 时使用 runtime.NumCPU()
+	QueueSize int  // 待处理任务队列长度，0表示无缓冲
+	Ordered   bool // 为true时结果按提交顺序输出，否则按完成顺序
+}
+
+// Result 一个任务的处理结果
+type Result[In, Out any] struct {
+	Index int // 提交顺序，从0开始
+	Input In
+	Value Out
+	Err   error
+}
+
+// PanicError 任务发生panic时的错误，包含panic的值和调用栈
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("任务panic: %v", e.Value)
+}
+
+type job[In any] struct {
+	index int
+	input In
+}
+
+type workerKey struct{}
+
+// WorkerID 返回处理当前任务的worker编号（从1开始），不在工作池中时返回0
+func WorkerID(ctx context.Context) int {
+	id, _ := ctx.Value(workerKey{}).(int)
+	return id
+}
+
+// Pool 泛型工作池
+//
+// 典型用法：一个goroutine提交任务后调用 Shutdown，
+// 另一个goroutine读取 Results 直到关闭，最后调用 Wait 获取错误。
+// 调用者必须读取 Results，否则worker会阻塞在发送结果上。
+type Pool[In, Out any] struct {
+	ctx     context.Context
+	task    Task[In, Out]
+	ordered bool
+
+	mu      sync.Mutex
+	closed  bool
+	next    int
+	skipped map[int]bool   // 已分配编号但因取消没有送入队列的任务
+	sending sync.WaitGroup // 正在向队列发送的 Submit，全部结束后才能关闭队列
+
+	jobs    chan job[In]
+	raw     chan Result[In, Out]
+	results chan Result[In, Out]
+	workers sync.WaitGroup
+	done    chan struct{}
+
+	errMu sync.Mutex
+	errs  []error
+}
+
+// New 创建并启动工作池，ctx取消后尚未开始的任务以 ctx.Err() 作为结果
+func New[In, Out any](ctx context.Context, task Task[In, Out], opts Options) *Pool[In, Out] {
+	n := opts.Workers
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+
+	p := &Pool[In, Out]{
+		ctx:     ctx,
+		task:    task,
+		ordered: opts.Ordered,
+		jobs:    make(chan job[In], opts.QueueSize),
+		raw:     make(chan Result[In, Out]),
+		results: make(chan Result[In, Out]),
+		done:    make(chan struct{}),
+		skipped: make(map[int]bool),
+	}
+
+	for id := 1; id <= n; id++ {
+		p.workers.Add(1)
+		go p.work(id)
+	}
+	go func() {
+		p.workers.Wait()
+		close(p.raw)
+	}()
+	go p.collect()
+
+	return p
+}
+
+// Submit 提交一个任务，队列满时阻塞，直到有空位或ctx被取消
+func (p *Pool[In, Out]) Submit(in In) error {
+	// 只在锁内分配编号，阻塞发送时不持有锁，否则 Shutdown 和其他 Submit 都会被卡住
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	index := p.next
+	p.next++
+	p.sending.Add(1)
+	p.mu.Unlock()
+	defer p.sending.Done()
+
+	select {
+	case p.jobs <- job[In]{index: index, input: in}:
+		return nil
+	case <-p.ctx.Done():
+		// 编号已经分配出去，记录下来让按顺序输出时跳过它
+		p.mu.Lock()
+		p.skipped[index] = true
+		p.mu.Unlock()
+		return p.ctx.Err()
+	}
+}
+
+// Results 返回结果channel，所有任务处理完并 Shutdown 后关闭
+func (p *Pool[In, Out]) Results() <-chan Result[In, Out] {
+	return p.results
+}
+
+// Shutdown 停止接收新任务，已提交的任务会继续处理，可以重复调用
+// 不会等待阻塞中的 Submit：队列在它们全部返回后才关闭
+func (p *Pool[In, Out]) Shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		go func() {
+			p.sending.Wait()
+			close(p.jobs)
+		}()
+	}
+}
+
+// Wait 等待所有任务处理完、结果全部被读取，返回所有任务错误的合并
+// 必须先调用 Shutdown
+func (p *Pool[In, Out]) Wait() error {
+	<-p.done
+	return p.Err()
+}
+
+// Err 返回目前为止收集到的任务错误
+func (p *Pool[In, Out]) Err() error {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+func (p *Pool[In, Out]) work(id int) {
+	defer p.workers.Done()
+	ctx := context.WithValue(p.ctx, workerKey{}, id)
+
+	for j := range p.jobs {
+		r := Result[In, Out]{Index: j.index, Input: j.input}
+		if err := ctx.Err(); err != nil {
+			r.Err = err
+		} else {
+			r.Value, r.Err = p.run(ctx, j.input)
+		}
+		if r.Err != nil {
+			p.errMu.Lock()
+			p.errs = append(p.errs, fmt.Errorf("任务 %d: %w", r.Index, r.Err))
+			p.errMu.Unlock()
+		}
+		p.raw <- r
+	}
+}
+
+// run 执行任务，把panic转换为 *PanicError，一个任务panic不影响其他任务
+func (p *Pool[In, Out]) run(ctx context.Context, in In) (out Out, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return p.task(ctx, in)
+}
+
+// collect 把worker的结果转发给调用者，需要时按编号重新排序
+func (p *Pool[In, Out]) collect() {
+	defer close(p.done)
+	defer close(p.results)
+
+	if !p.ordered {
+		for r := range p.raw {
+			p.results <- r
+		}
+		return
+	}
+
+	pending := make(map[int]Result[In, Out])
+	next := 0
+	for r := range p.raw {
+		pending[r.Index] = r
+		for {
+			if r, ok := pending[next]; ok {
+				delete(pending, next)
+				p.results <- r
+			} else if !p.isSkipped(next) {
+				break
+			}
+			next++
+		}
+	}
+
+	// 被跳过的编号可能在最后一个结果之后才登记，剩下的结果按编号依次输出
+	rest := make([]int, 0, len(pending))
+	for i := range pending {
+		rest = append(rest, i)
+	}
+	sort.Ints(rest)
+	for _, i := range rest {
+		p.results <- pending[i]
+	}
+}
+
+func (p *Pool[In, Out]) isSkipped(index int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.skipped[index]
+}
+
+// Map 用工作池处理inputs，返回与inputs顺序一致的结果和所有错误的合并
+func Map[In, Out any](ctx context.Context, inputs []In, task Task[In, Out], opts Options) ([]Out, error) {
+	p := New(ctx, task, opts)
+	// 提交失败（通常是ctx被取消）时剩下的输入没有结果，必须把错误返回给调用者
+	submitErr := make(chan error, 1)
+	go func() {
+		defer p.Shutdown()
+		for _, in := range inputs {
+			if err := p.Submit(in); err != nil {
+				submitErr <- err
+				return
+			}
+		}
+		submitErr <- nil
+	}()
+
+	out := make([]Out, len(inputs))
+	for r := range p.Results() {
+		out[r.Index] = r.Value
+	}
+	err := p.Wait()
+	return out, errors.Join(<-submitErr, err)
+}
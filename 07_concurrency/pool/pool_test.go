@@ -0,0 +1,243 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+// within 在timeout内等待fn返回，超时则测试失败
+func within(t *testing.T, timeout time.Duration, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("%s 在 %v 内没有返回", what, timeout)
+	}
+}
+
+func drain[In, Out any](p *Pool[In, Out]) []Result[In, Out] {
+	var rs []Result[In, Out]
+	for r := range p.Results() {
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func TestOrdered(t *testing.T) {
+	leakcheck.Verify(t)
+	square := func(ctx context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		return n * n, nil
+	}
+	p := New(context.Background(), square, Options{Workers: 4, QueueSize: 2, Ordered: true})
+	go func() {
+		defer p.Shutdown()
+		for i := 0; i < 50; i++ {
+			p.Submit(i)
+		}
+	}()
+
+	rs := drain(p)
+	if len(rs) != 50 {
+		t.Fatalf("得到 %d 个结果，期望 50", len(rs))
+	}
+	for i, r := range rs {
+		if r.Index != i || r.Input != i || r.Value != i*i {
+			t.Fatalf("第%d个结果为 %+v", i, r)
+		}
+	}
+	if err := p.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestErrorsAndPanics(t *testing.T) {
+	leakcheck.Verify(t)
+	task := func(ctx context.Context, n int) (string, error) {
+		switch n {
+		case 1:
+			return "", errors.New("失败")
+		case 2:
+			panic("崩溃")
+		}
+		return fmt.Sprint(n), nil
+	}
+
+	out, err := Map(context.Background(), []int{0, 1, 2, 3}, task, Options{Workers: 2})
+	if out[0] != "0" || out[3] != "3" {
+		t.Errorf("结果 = %q", out)
+	}
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "崩溃" || len(pe.Stack) == 0 {
+		t.Errorf("错误中没有 *PanicError: %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "任务 1") || !strings.Contains(err.Error(), "任务 2") {
+		t.Errorf("错误 = %v，期望包含任务1和任务2", err)
+	}
+}
+
+func TestWorkerID(t *testing.T) {
+	leakcheck.Verify(t)
+	if WorkerID(context.Background()) != 0 {
+		t.Error("不在工作池中时 WorkerID 应为0")
+	}
+	ids, _ := Map(context.Background(), make([]int, 20), func(ctx context.Context, _ int) (int, error) {
+		return WorkerID(ctx), nil
+	}, Options{Workers: 3})
+	for _, id := range ids {
+		if id < 1 || id > 3 {
+			t.Fatalf("WorkerID = %d，期望 1-3", id)
+		}
+	}
+}
+
+func TestSubmitAfterShutdown(t *testing.T) {
+	leakcheck.Verify(t)
+	p := New(context.Background(), func(context.Context, int) (int, error) { return 0, nil }, Options{Workers: 1})
+	p.Shutdown()
+	p.Shutdown() // 可以重复调用
+	if err := p.Submit(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Shutdown 后 Submit 返回 %v，期望 ErrClosed", err)
+	}
+	drain(p)
+	p.Wait()
+}
+
+// blockingPool 返回一个只有1个worker、无缓冲队列的工作池，任务阻塞到release关闭或ctx取消
+func blockingPool(ctx context.Context, ordered bool) (*Pool[int, int], chan struct{}, *atomic.Int32) {
+	release := make(chan struct{})
+	var started atomic.Int32
+	p := New(ctx, func(ctx context.Context, n int) (int, error) {
+		started.Add(1)
+		select {
+		case <-release:
+			return n, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}, Options{Workers: 1, Ordered: ordered})
+	return p, release, &started
+}
+
+func waitStarted(t *testing.T, started *atomic.Int32, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for started.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d 个任务没有开始", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// 队列已满时取消ctx，阻塞中的 Submit 必须返回，Shutdown 也不能被卡住
+func TestSubmitUnblocksOnCancel(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, _, started := blockingPool(ctx, true)
+	results := make(chan []Result[int, int])
+	go func() { results <- drain(p) }()
+
+	if err := p.Submit(0); err != nil {
+		t.Fatal(err)
+	}
+	waitStarted(t, started, 1)
+
+	submitErr := make(chan error)
+	go func() { submitErr <- p.Submit(1) }()
+	time.Sleep(10 * time.Millisecond) // 让第二个 Submit 阻塞在队列上
+	within(t, time.Second, "Submit阻塞时的Shutdown", p.Shutdown)
+
+	cancel()
+	select {
+	case err := <-submitErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Submit 返回 %v，期望 context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消ctx后 Submit 仍然阻塞")
+	}
+
+	// 被取消的任务1占用了编号，按顺序输出时必须跳过它而不是一直等待
+	var rs []Result[int, int]
+	select {
+	case rs = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("结果channel没有关闭")
+	}
+	if len(rs) != 1 || rs[0].Index != 0 || !errors.Is(rs[0].Err, context.Canceled) {
+		t.Errorf("结果 = %+v，期望只有被取消的任务0", rs)
+	}
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait 返回 %v", err)
+	}
+}
+
+// Shutdown 不等待阻塞中的 Submit，被阻塞的任务之后仍会被处理
+func TestShutdownDoesNotDropBlockedSubmit(t *testing.T) {
+	leakcheck.Verify(t)
+	p, release, started := blockingPool(context.Background(), true)
+	results := make(chan []Result[int, int])
+	go func() { results <- drain(p) }()
+
+	p.Submit(0)
+	waitStarted(t, started, 1)
+	submitErr := make(chan error)
+	go func() { submitErr <- p.Submit(1) }()
+	time.Sleep(10 * time.Millisecond)
+
+	within(t, time.Second, "Shutdown", p.Shutdown)
+	close(release)
+
+	if err := <-submitErr; err != nil {
+		t.Errorf("Shutdown 之前开始的 Submit 返回 %v", err)
+	}
+	rs := <-results
+	if len(rs) != 2 || rs[0].Value != 0 || rs[1].Value != 1 {
+		t.Errorf("结果 = %+v，期望任务0和1", rs)
+	}
+	if err := p.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubmitAfterCancel(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	p, _, _ := blockingPool(ctx, false)
+	cancel()
+	if err := p.Submit(1); !errors.Is(err, context.Canceled) {
+		t.Errorf("取消后 Submit 返回 %v", err)
+	}
+	p.Shutdown()
+	if rs := drain(p); len(rs) != 0 {
+		t.Errorf("结果 = %+v，期望为空", rs)
+	}
+	p.Wait()
+}
+
+// TestMapCancelled ctx已取消时 Map 不能返回零值结果和nil错误
+func TestMapCancelled(t *testing.T) {
+	leakcheck.Verify(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	square := func(ctx context.Context, n int) (int, error) { return n * n, nil }
+	_, err := Map(ctx, []int{1, 2, 3}, square, Options{Workers: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Map 返回 %v，期望 context.Canceled", err)
+	}
+}
@@ -0,0 +1,120 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBrokenBarrier 屏障被打破：有参与者取消了等待，或调用了 Reset
+var ErrBrokenBarrier = errors.New("屏障已被打破")
+
+// Barrier 循环屏障：parties个goroutine都到达后一起继续，然后屏障自动重置供下一轮使用
+type Barrier struct {
+	parties int
+	action  func()
+
+	mu      sync.Mutex
+	arrived int
+	gen     *generation
+}
+
+// generation 屏障的一轮
+type generation struct {
+	done   chan struct{}
+	broken bool
+}
+
+// NewBarrier 创建屏障，action不为nil时由最后一个到达的goroutine在放行前执行
+// action执行时持有屏障的锁，不能再调用屏障的方法；屏障被打破后需要 Reset 才能继续使用
+func NewBarrier(parties int, action func()) *Barrier {
+	if parties <= 0 {
+		panic("primitives: 屏障的参与者数量必须大于0")
+	}
+	return &Barrier{parties: parties, action: action, gen: &generation{done: make(chan struct{})}}
+}
+
+// Await 到达屏障并等待其他参与者
+// 返回到达的顺序，parties-1 表示第一个到达，0 表示最后一个到达
+func (b *Barrier) Await(ctx context.Context) (int, error) {
+	b.mu.Lock()
+	g := b.gen
+	if g.broken {
+		b.mu.Unlock()
+		return 0, ErrBrokenBarrier
+	}
+
+	b.arrived++
+	index := b.parties - b.arrived
+	if index == 0 {
+		if b.action != nil {
+			b.action()
+		}
+		b.next()
+		b.mu.Unlock()
+		return 0, nil
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-g.done:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if g.broken {
+			return index, ErrBrokenBarrier
+		}
+		return index, nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// 同时完成的话以完成为准
+		select {
+		case <-g.done:
+			if !g.broken {
+				return index, nil
+			}
+		default:
+			b.breakBarrier()
+		}
+		return index, ctx.Err()
+	}
+}
+
+// Reset 打破当前一轮（正在等待的参与者收到 ErrBrokenBarrier），然后开始新的一轮
+func (b *Barrier) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.breakBarrier()
+	b.next()
+}
+
+// Waiting 返回当前一轮已到达、正在等待的参与者数量
+func (b *Barrier) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.arrived
+}
+
+// Broken 返回当前一轮是否已被打破
+func (b *Barrier) Broken() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.gen.broken
+}
+
+// next 放行当前一轮并开始新的一轮，调用时持有锁
+func (b *Barrier) next() {
+	if !b.gen.broken {
+		close(b.gen.done)
+	}
+	b.arrived = 0
+	b.gen = &generation{done: make(chan struct{})}
+}
+
+// breakBarrier 打破当前一轮，调用时持有锁
+func (b *Barrier) breakBarrier() {
+	if !b.gen.broken {
+		b.gen.broken = true
+		close(b.gen.done)
+	}
+}
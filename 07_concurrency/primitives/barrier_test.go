@@ -0,0 +1,138 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+// awaitAsync 在后台等待屏障，返回接收结果的channel
+func awaitAsync(ctx context.Context, b *Barrier) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		_, err := b.Await(ctx)
+		errc <- err
+	}()
+	return errc
+}
+
+// arrived 等待屏障上有n个参与者在等待
+func arrived(t *testing.T, b *Barrier, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for b.Waiting() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待的参与者为 %d，期望 %d", b.Waiting(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBarrierCycles(t *testing.T) {
+	leakcheck.Verify(t)
+	const parties, rounds = 3, 4
+	var mu sync.Mutex
+	actions := 0
+	b := NewBarrier(parties, func() { actions++ })
+
+	indexes := make([][]int, rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < parties; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				index, err := b.Await(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				indexes[r] = append(indexes[r], index)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if actions != rounds {
+		t.Errorf("action 执行了 %d 次，期望 %d", actions, rounds)
+	}
+	for r, got := range indexes {
+		sort.Ints(got)
+		if len(got) != parties || got[0] != 0 || got[1] != 1 || got[2] != 2 {
+			t.Errorf("第%d轮的到达顺序 %v，期望 [0 1 2]", r+1, got)
+		}
+	}
+}
+
+// TestBarrierBreak 一个参与者取消后屏障被打破，其他参与者收到 ErrBrokenBarrier，Reset 后恢复
+func TestBarrierBreak(t *testing.T) {
+	leakcheck.Verify(t)
+	b := NewBarrier(3, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := awaitAsync(ctx, b)
+	other := awaitAsync(context.Background(), b)
+	arrived(t, b, 2)
+
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("取消的参与者返回 %v，期望 context.Canceled", err)
+	}
+	if err := <-other; !errors.Is(err, ErrBrokenBarrier) {
+		t.Errorf("其他参与者返回 %v，期望 ErrBrokenBarrier", err)
+	}
+	if !b.Broken() {
+		t.Error("屏障应处于打破状态")
+	}
+	if _, err := b.Await(context.Background()); !errors.Is(err, ErrBrokenBarrier) {
+		t.Errorf("打破后 Await 返回 %v，期望 ErrBrokenBarrier", err)
+	}
+
+	b.Reset()
+	if b.Broken() || b.Waiting() != 0 {
+		t.Fatalf("Reset 后 Broken=%v Waiting=%d", b.Broken(), b.Waiting())
+	}
+	errs := []<-chan error{awaitAsync(context.Background(), b), awaitAsync(context.Background(), b)}
+	arrived(t, b, 2)
+	if _, err := b.Await(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, errc := range errs {
+		if err := <-errc; err != nil {
+			t.Errorf("Reset 后的一轮返回 %v", err)
+		}
+	}
+}
+
+func TestBarrierReset(t *testing.T) {
+	leakcheck.Verify(t)
+	b := NewBarrier(3, nil)
+	errs := []<-chan error{awaitAsync(context.Background(), b), awaitAsync(context.Background(), b)}
+	arrived(t, b, 2)
+
+	b.Reset() // 正在等待的参与者被唤醒
+	for _, errc := range errs {
+		if err := <-errc; !errors.Is(err, ErrBrokenBarrier) {
+			t.Errorf("Reset 时等待的参与者返回 %v，期望 ErrBrokenBarrier", err)
+		}
+	}
+	if b.Broken() || b.Waiting() != 0 {
+		t.Errorf("Reset 后 Broken=%v Waiting=%d", b.Broken(), b.Waiting())
+	}
+}
+
+func TestBarrierInvalidParties(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("参与者数量为0应panic")
+		}
+	}()
+	NewBarrier(0, nil)
+}
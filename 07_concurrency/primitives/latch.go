@@ -0,0 +1,59 @@
+package primitives
+
+import (
+	"context"
+	"sync"
+)
+
+// Latch 倒计时门闩：计数减到0时放行所有等待者，之后不能重置
+// 与 sync.WaitGroup 相比，Wait 可以被ctx取消，计数也可以在等待开始后由任意goroutine递减
+type Latch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// NewLatch 创建计数为count的门闩，count为0时一开始就是打开的
+func NewLatch(count int) *Latch {
+	l := &Latch{count: count, done: make(chan struct{})}
+	if count <= 0 {
+		l.count = 0
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown 计数减一，已经为0时不做任何事
+func (l *Latch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Count 返回当前计数
+func (l *Latch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Done 返回计数为0时关闭的channel，可以在select中使用
+func (l *Latch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Wait 等待计数减到0
+func (l *Latch) Wait(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
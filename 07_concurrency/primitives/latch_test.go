@@ -0,0 +1,72 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+func TestLatchOpensAtZero(t *testing.T) {
+	leakcheck.Verify(t)
+	const n = 10
+	l := NewLatch(n)
+
+	errc := make(chan error, 1)
+	go func() { errc <- l.Wait(context.Background()) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n-1; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.CountDown()
+		}()
+	}
+	wg.Wait()
+	if l.Count() != 1 {
+		t.Fatalf("Count = %d，期望 1", l.Count())
+	}
+	select {
+	case <-l.Done():
+		t.Fatal("计数没到0就打开了")
+	case err := <-errc:
+		t.Fatalf("计数没到0 Wait 就返回了 %v", err)
+	default:
+	}
+
+	l.CountDown()
+	if err := <-errc; err != nil {
+		t.Errorf("Wait 返回 %v", err)
+	}
+	<-l.Done()
+
+	l.CountDown() // 已经为0时不做任何事
+	if l.Count() != 0 {
+		t.Errorf("Count = %d，期望 0", l.Count())
+	}
+}
+
+func TestLatchZeroCount(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		l := NewLatch(n)
+		if err := l.Wait(context.Background()); err != nil || l.Count() != 0 {
+			t.Errorf("NewLatch(%d): Wait=%v Count=%d，期望一开始就打开", n, err, l.Count())
+		}
+	}
+}
+
+func TestLatchWaitCancel(t *testing.T) {
+	l := NewLatch(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait 返回 %v，期望 DeadlineExceeded", err)
+	}
+	if l.Count() != 1 {
+		t.Error("取消等待不应改变计数")
+	}
+}
@@ -0,0 +1,93 @@
+package primitives
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+)
+
+// Limiter 令牌桶限流器：每秒补充rate个令牌，最多积攒burst个
+// 时间来自 clock.Clock，换成假时钟就能确定性地检查限流效果
+type Limiter struct {
+	clock clock.Clock
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter 创建限流器，桶初始是满的；clk为nil时使用真实时钟
+func NewLimiter(clk clock.Clock, rate float64, burst int) *Limiter {
+	if clk == nil {
+		clk = clock.Real
+	}
+	if rate <= 0 || burst <= 0 {
+		panic("primitives: 限流器的速率和容量必须大于0")
+	}
+	return &Limiter{clock: clk, rate: rate, burst: float64(burst), tokens: float64(burst), last: clk.Now()}
+}
+
+// refill 按流逝的时间补充令牌，调用时持有锁
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// Allow 有令牌时取走一个并返回true，不等待
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.clock.Now())
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
+
+// Wait 等待并取走一个令牌
+func (l *Limiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN 等待并取走n个令牌
+// 令牌先预留（可以欠账），按欠账计算需要等待的时间，ctx取消时归还预留的令牌
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("请求的令牌数 %d 必须大于0", n)
+	}
+	if float64(n) > l.burst {
+		return fmt.Errorf("请求的令牌数 %d 超过桶容量 %g", n, l.burst)
+	}
+
+	l.mu.Lock()
+	l.refill(l.clock.Now())
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := l.clock.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		// 归还预留的令牌，期间可能已经补充了一些，归还后不能超过桶容量
+		l.mu.Lock()
+		l.refill(l.clock.Now())
+		l.tokens = min(l.burst, l.tokens+float64(n))
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+	"go-learn/07_concurrency/leakcheck"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// drain 不等待地取走所有令牌，返回取到的个数
+func drain(l *Limiter) int {
+	n := 0
+	for l.Allow() {
+		n++
+	}
+	return n
+}
+
+func TestLimiterAllow(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	l := NewLimiter(fake, 2, 3)
+
+	if n := drain(l); n != 3 {
+		t.Errorf("初始突发 %d 个，期望桶容量 3", n)
+	}
+	fake.Advance(500 * time.Millisecond)
+	if n := drain(l); n != 1 {
+		t.Errorf("0.5秒后补充了 %d 个，期望 1", n)
+	}
+	fake.Advance(time.Hour)
+	if n := drain(l); n != 3 {
+		t.Errorf("长时间空闲后取到 %d 个，期望不超过桶容量 3", n)
+	}
+}
+
+func TestLimiterWaitN(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	l := NewLimiter(fake, 2, 4)
+	drain(l)
+
+	errc := make(chan error, 1)
+	go func() { errc <- l.WaitN(context.Background(), 3) }()
+
+	// 欠3个令牌，每秒2个，需要等1.5秒
+	fake.BlockUntil(1)
+	if d, _ := fake.NextDeadline(); d.Sub(epoch) != 1500*time.Millisecond {
+		t.Errorf("等到 %v，期望 1.5s", d.Sub(epoch))
+	}
+	fake.Advance(1500 * time.Millisecond)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if l.Allow() {
+		t.Error("等到的令牌应已被用掉")
+	}
+}
+
+func TestLimiterWaitNInvalid(t *testing.T) {
+	l := NewLimiter(clock.NewFake(epoch), 1, 2)
+	for _, n := range []int{0, -1, 3} {
+		if err := l.WaitN(context.Background(), n); err == nil {
+			t.Errorf("WaitN(%d) 应返回错误", n)
+		}
+	}
+	if n := drain(l); n != 2 {
+		t.Errorf("非法请求之后取到 %d 个令牌，期望 2", n)
+	}
+}
+
+// TestLimiterCancelRefund 取消时归还预留的令牌，归还后不超过桶容量
+func TestLimiterCancelRefund(t *testing.T) {
+	leakcheck.Verify(t)
+	fake := clock.NewFake(epoch)
+	l := NewLimiter(fake, 1, 2)
+	drain(l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- l.WaitN(ctx, 2) }()
+	fake.BlockUntil(1)
+	fake.Advance(1500 * time.Millisecond) // 还差0.5秒
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("WaitN 返回 %v，期望 context.Canceled", err)
+	}
+
+	// 预留的2个归还后，0.5秒前开始补充的1.5个令牌都在
+	if n := drain(l); n != 1 {
+		t.Errorf("取消后取到 %d 个令牌，期望 1", n)
+	}
+	fake.Advance(time.Hour)
+	if n := drain(l); n != 2 {
+		t.Errorf("取到 %d 个令牌，期望不超过桶容量 2", n)
+	}
+}
+
+func TestLimiterInvalid(t *testing.T) {
+	for _, c := range []struct {
+		rate  float64
+		burst int
+	}{{0, 1}, {1, 0}, {-1, 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewLimiter(%v, %d) 应panic", c.rate, c.burst)
+				}
+			}()
+			NewLimiter(nil, c.rate, c.burst)
+		}()
+	}
+}
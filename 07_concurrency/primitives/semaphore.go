@@ -0,0 +1,117 @@
+// Package primitives 补充sync包之外常用的同步原语：
+// 带权信号量、循环屏障、倒计时门闩、令牌桶限流器和重复请求合并
+package primitives
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Semaphore 带权信号量：总容量为size，每次可以获取任意权重
+// 等待者按先来先得的顺序获得名额，大请求不会被源源不断的小请求饿死
+type Semaphore struct {
+	size    int64
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List // 元素为 *semWaiter
+}
+
+type semWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore 创建容量为size的信号量
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+// Acquire 获取权重为n的名额，ctx取消时返回ctx的错误且不占用名额
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n <= 0 {
+		return fmt.Errorf("请求的权重 %d 必须大于0", n)
+	}
+	s.mu.Lock()
+	if n > s.size {
+		s.mu.Unlock()
+		return fmt.Errorf("请求的权重 %d 超过信号量容量 %d", n, s.size)
+	}
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := &semWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// 取消的同时已经拿到了名额，归还它
+			s.cur -= n
+			s.notify()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// 排在最前面的等待者离开后，后面的可能已经可以获取
+			if isFront {
+				s.notify()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire 不等待地尝试获取，成功返回true；n<=0 时返回false
+func (s *Semaphore) TryAcquire(n int64) bool {
+	if n <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release 归还权重为n的名额
+func (s *Semaphore) Release(n int64) {
+	if n <= 0 {
+		panic("primitives: 归还的权重必须大于0")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("primitives: 归还的名额多于获取的名额")
+	}
+	s.notify()
+}
+
+// notify 按顺序唤醒能满足的等待者，遇到满足不了的就停下，保证先来先得
+func (s *Semaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*semWaiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,191 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+// queued 等待信号量上恰好有n个等待者
+func queued(t *testing.T, s *Semaphore, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		got := s.waiters.Len()
+		s.mu.Unlock()
+		if got == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("等待者数量为 %d，期望 %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// acquireAsync 在后台获取名额，返回接收结果的channel
+func acquireAsync(ctx context.Context, s *Semaphore, n int64) <-chan error {
+	errc := make(chan error, 1)
+	go func() { errc <- s.Acquire(ctx, n) }()
+	return errc
+}
+
+// pending 确认获取仍在等待
+func pending(t *testing.T, errc <-chan error) {
+	t.Helper()
+	select {
+	case err := <-errc:
+		t.Fatalf("获取没有等待，返回 %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// acquired 等待获取返回
+func acquired(t *testing.T, errc <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("获取一直没有返回")
+		return nil
+	}
+}
+
+func TestSemaphoreTryAcquire(t *testing.T) {
+	s := NewSemaphore(3)
+	if !s.TryAcquire(2) || !s.TryAcquire(1) {
+		t.Fatal("容量足够时 TryAcquire 应成功")
+	}
+	if s.TryAcquire(1) {
+		t.Error("容量用完后 TryAcquire 应失败")
+	}
+	s.Release(3)
+	if !s.TryAcquire(3) {
+		t.Error("归还后 TryAcquire 应成功")
+	}
+}
+
+func TestSemaphoreInvalidWeight(t *testing.T) {
+	s := NewSemaphore(3)
+	for _, n := range []int64{0, -1, 4} {
+		if err := s.Acquire(context.Background(), n); err == nil {
+			t.Errorf("Acquire(%d) 应返回错误", n)
+		}
+	}
+	if s.TryAcquire(0) || s.TryAcquire(-2) {
+		t.Error("TryAcquire 非正数应返回false")
+	}
+	// 非法的请求不应改变名额
+	if !s.TryAcquire(3) {
+		t.Error("非法请求之后名额被占用了")
+	}
+
+	for _, n := range []int64{-1, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Release(%d) 应panic", n)
+				}
+			}()
+			s.Release(n)
+		}()
+	}
+}
+
+// TestSemaphoreFIFO 大请求排在前面时，后来的小请求即使名额够也要排队
+func TestSemaphoreFIFO(t *testing.T) {
+	leakcheck.Verify(t)
+	s := NewSemaphore(3)
+	ctx := context.Background()
+	s.Acquire(ctx, 3)
+
+	big := acquireAsync(ctx, s, 2)
+	queued(t, s, 1)
+	small := acquireAsync(ctx, s, 1)
+	queued(t, s, 2)
+
+	s.Release(1) // 空出1个，够小请求但不够排在前面的大请求
+	pending(t, small)
+	if s.TryAcquire(1) {
+		t.Error("有人排队时 TryAcquire 不应插队")
+	}
+
+	s.Release(1) // 空出2个，大请求先拿到
+	if err := acquired(t, big); err != nil {
+		t.Fatal(err)
+	}
+	pending(t, small)
+
+	s.Release(1)
+	if err := acquired(t, small); err != nil {
+		t.Fatal(err)
+	}
+	s.Release(3)
+}
+
+// TestSemaphoreCancel 取消排在最前面的等待者，后面能满足的等待者立即获得名额
+func TestSemaphoreCancel(t *testing.T) {
+	leakcheck.Verify(t)
+	s := NewSemaphore(2)
+	s.Acquire(context.Background(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	big := acquireAsync(ctx, s, 2)
+	queued(t, s, 1)
+	small := acquireAsync(context.Background(), s, 1)
+	queued(t, s, 2)
+	pending(t, small)
+
+	cancel()
+	if err := acquired(t, big); !errors.Is(err, context.Canceled) {
+		t.Errorf("取消后返回 %v，期望 context.Canceled", err)
+	}
+	if err := acquired(t, small); err != nil {
+		t.Fatal(err)
+	}
+
+	// 取消不占用名额：归还后可以拿满
+	s.Release(2)
+	if !s.TryAcquire(2) {
+		t.Error("取消的请求占用了名额")
+	}
+}
+
+func TestSemaphoreConcurrent(t *testing.T) {
+	leakcheck.Verify(t)
+	const size = 5
+	s := NewSemaphore(size)
+	var active, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			if err := s.Acquire(context.Background(), n); err != nil {
+				t.Error(err)
+				return
+			}
+			cur := active.Add(n)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-n)
+			s.Release(n)
+		}(int64(i%size + 1))
+	}
+	wg.Wait()
+	if p := peak.Load(); p > size {
+		t.Errorf("同时占用 %d 个名额，超过容量 %d", p, size)
+	}
+}
@@ -0,0 +1,67 @@
+package primitives
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Group 合并对同一个key的并发调用：同一时刻只有一个调用真正执行，
+// 其他调用者等待并共享它的结果，常用于防止缓存击穿
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+type call[V any] struct {
+	wg   sync.WaitGroup
+	val  V
+	err  error
+	dups int
+}
+
+// Do 执行fn并返回结果；同一key已有调用在执行时，等待并返回那次调用的结果
+// shared表示结果是否被多个调用者共享；fn发生panic时转换为错误返回给所有调用者
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c.err = fmt.Errorf("singleflight: %v", r)
+			}
+		}()
+		c.val, c.err = fn()
+	}()
+
+	g.mu.Lock()
+	// Forget 之后可能已有新的调用占用了这个key
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	shared = c.dups > 0
+	g.mu.Unlock()
+	c.wg.Done()
+
+	return c.val, c.err, shared
+}
+
+// Forget 让之后对key的调用不再等待正在执行的那一次，而是重新执行
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
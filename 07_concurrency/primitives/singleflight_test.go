@@ -0,0 +1,116 @@
+package primitives
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+func TestGroupDedup(t *testing.T) {
+	leakcheck.Verify(t)
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const n = 10
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do("key", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("Do = %d, %v", v, err)
+			}
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+
+	// 等其他调用者都在等待第一次调用，再放行
+	for {
+		g.mu.Lock()
+		c := g.calls["key"]
+		waiting := c != nil && c.dups == n-1
+		g.mu.Unlock()
+		if waiting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn 执行了 %d 次，期望 1", calls.Load())
+	}
+	if shared.Load() != n {
+		t.Errorf("%d 个调用者的结果是共享的，期望 %d", shared.Load(), n)
+	}
+}
+
+func TestGroupSequential(t *testing.T) {
+	var g Group[string, int]
+	calls := 0
+	for i := 0; i < 3; i++ {
+		v, _, shared := g.Do("key", func() (int, error) {
+			calls++
+			return calls, nil
+		})
+		if v != i+1 || shared {
+			t.Errorf("第%d次 Do = %d, shared=%v", i+1, v, shared)
+		}
+	}
+}
+
+func TestGroupErrorAndPanic(t *testing.T) {
+	var g Group[int, string]
+	want := errors.New("查询失败")
+	if _, err, _ := g.Do(1, func() (string, error) { return "", want }); !errors.Is(err, want) {
+		t.Errorf("Do 返回 %v，期望 %v", err, want)
+	}
+	if _, err, _ := g.Do(2, func() (string, error) { panic("崩溃") }); err == nil {
+		t.Error("fn panic 时应返回错误")
+	}
+	// panic 之后同一个key还能继续使用
+	if v, err, _ := g.Do(2, func() (string, error) { return "ok", nil }); v != "ok" || err != nil {
+		t.Errorf("Do = %q, %v", v, err)
+	}
+}
+
+func TestGroupForget(t *testing.T) {
+	leakcheck.Verify(t)
+	var g Group[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		v, _, _ := g.Do("key", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		done <- v
+	}()
+	<-started
+
+	// Forget 之后的调用不再等待正在执行的那一次
+	g.Forget("key")
+	if v, _, shared := g.Do("key", func() (int, error) { return 2, nil }); v != 2 || shared {
+		t.Errorf("Forget 后 Do = %d, shared=%v，期望重新执行", v, shared)
+	}
+	close(release)
+	if v := <-done; v != 1 {
+		t.Errorf("第一次调用返回 %d，期望 1", v)
+	}
+}
@@ -0,0 +1,293 @@
+// Package pubsub 进程内的发布/订阅消息总线
+//
+// 主题用点号分隔，如 orders.created。订阅时可以使用通配符：
+// 星号(*)匹配恰好一段，如 orders.* 匹配 orders.created，不匹配 orders.eu.created；
+// 井号(#)只能放在最后，匹配零段或多段，如 orders.# 匹配 orders 和 orders.eu.created。
+//
+// 每个订阅者有自己的缓冲channel，缓冲满时按订阅时选择的 Policy 处理。
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed 总线关闭后发布或订阅时返回
+var ErrClosed = errors.New("消息总线已关闭")
+
+// Policy 订阅者缓冲满时的处理策略
+type Policy int
+
+const (
+	Block      Policy = iota // 阻塞发布者，直到订阅者读取（或ctx取消）
+	DropOldest               // 丢弃缓冲中最旧的消息，放入新消息
+	DropNewest               // 丢弃新消息
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Message 一条消息
+type Message[T any] struct {
+	Topic   string
+	Payload T
+}
+
+// Options 订阅选项
+type Options struct {
+	Buffer int // 缓冲大小，丢弃策略下至少为1
+	Policy Policy
+}
+
+// Bus 消息总线，零值不可用，使用 New 创建
+type Bus[T any] struct {
+	mu   sync.RWMutex
+	subs map[*Subscription[T]]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{} // 关闭时close，唤醒阻塞中的发布者
+}
+
+// New 创建消息总线
+func New[T any]() *Bus[T] {
+	return &Bus[T]{
+		subs: make(map[*Subscription[T]]struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Subscription 一个订阅
+type Subscription[T any] struct {
+	bus     *Bus[T]
+	pattern []string
+	policy  Policy
+	ch      chan Message[T]
+	dropped atomic.Int64
+
+	// 投递时持有读锁，关闭ch时持有写锁，保证不会向已关闭的ch发送
+	mu   sync.RWMutex
+	once sync.Once
+	done chan struct{} // 取消订阅时close，唤醒阻塞在该订阅上的发布者
+}
+
+// Subscribe 订阅匹配pattern的主题
+func (b *Bus[T]) Subscribe(pattern string, opts Options) (*Subscription[T], error) {
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Policy != Block && opts.Buffer < 1 {
+		opts.Buffer = 1
+	}
+
+	s := &Subscription[T]{
+		bus:     b,
+		pattern: segments,
+		policy:  opts.Policy,
+		ch:      make(chan Message[T], opts.Buffer),
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed() {
+		return nil, ErrClosed
+	}
+	b.subs[s] = struct{}{}
+	return s, nil
+}
+
+// Publish 把消息发给所有匹配的订阅者，返回实际放入缓冲的订阅者数量
+// 有 Block 策略的订阅者缓冲满时会一直等待，ctx取消时返回ctx的错误；
+// 等待期间不持有总线的锁，不影响其他发布者和 Subscribe
+func (b *Bus[T]) Publish(ctx context.Context, topic string, payload T) (int, error) {
+	if topic == "" || strings.ContainsAny(topic, "*#") {
+		return 0, fmt.Errorf("无效的主题 %q", topic)
+	}
+	msg := Message[T]{Topic: topic, Payload: payload}
+	segments := strings.Split(topic, ".")
+
+	// 只在锁内找出匹配的订阅者，投递时不持有总线的锁
+	b.mu.RLock()
+	if b.closed() {
+		b.mu.RUnlock()
+		return 0, ErrClosed
+	}
+	var targets []*Subscription[T]
+	for s := range b.subs {
+		if match(s.pattern, segments) {
+			targets = append(targets, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	delivered := 0
+	for _, s := range targets {
+		ok, err := s.deliver(ctx, msg)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// Close 关闭总线：唤醒阻塞的发布者，并关闭所有订阅者的channel
+// 订阅者读完缓冲中剩余的消息后，range循环自然结束
+func (b *Bus[T]) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+
+		b.mu.Lock()
+		subs := b.subs
+		b.subs = make(map[*Subscription[T]]struct{})
+		b.mu.Unlock()
+		for s := range subs {
+			s.shutdown()
+		}
+	})
+}
+
+func (b *Bus[T]) closed() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// C 返回接收消息的channel，取消订阅或总线关闭后被关闭
+func (s *Subscription[T]) C() <-chan Message[T] {
+	return s.ch
+}
+
+// Dropped 返回因缓冲满而被丢弃的消息数
+func (s *Subscription[T]) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe 取消订阅，可以重复调用
+func (s *Subscription[T]) Unsubscribe() {
+	s.bus.mu.Lock()
+	delete(s.bus.subs, s)
+	s.bus.mu.Unlock()
+	s.shutdown()
+}
+
+// shutdown 关闭ch，可以重复调用
+// 先close done唤醒阻塞在本订阅上的发布者，它们释放读锁后才能关闭ch
+func (s *Subscription[T]) shutdown() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// deliver 按策略投递一条消息，返回消息是否放入了缓冲
+// 发布者取到快照之后订阅可能已被取消，这时不再投递
+func (s *Subscription[T]) deliver(ctx context.Context, msg Message[T]) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	select {
+	case <-s.done:
+		return false, s.closedErr()
+	default:
+	}
+
+	switch s.policy {
+	case DropNewest:
+		select {
+		case s.ch <- msg:
+			return true, nil
+		default:
+			s.dropped.Add(1)
+			return false, nil
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case s.ch <- msg:
+				return true, nil
+			default:
+			}
+			// 缓冲满了，取出最旧的一条腾出位置；订阅者可能同时在读，所以也不阻塞
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case s.ch <- msg:
+			return true, nil
+		case <-s.done:
+			return false, s.closedErr()
+		case <-s.bus.done:
+			return false, ErrClosed
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// closedErr 订阅关闭时投递的结果：总线关闭时返回 ErrClosed，只是取消订阅时返回nil
+// 总线先close done再关闭订阅，所以订阅因总线关闭而关闭时这里一定能看到
+func (s *Subscription[T]) closedErr() error {
+	if s.bus.closed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+// parsePattern 检查订阅模式并按段切分
+func parsePattern(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, errors.New("订阅模式不能为空")
+	}
+	segments := strings.Split(pattern, ".")
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+			return nil, fmt.Errorf("订阅模式 %q 中有空段", pattern)
+		case seg == "#" && i != len(segments)-1:
+			return nil, fmt.Errorf("订阅模式 %q 中 # 只能放在最后", pattern)
+		case seg != "*" && seg != "#" && strings.ContainsAny(seg, "*#"):
+			return nil, fmt.Errorf("订阅模式 %q 中通配符必须单独成段", pattern)
+		}
+	}
+	return segments, nil
+}
+
+// match 判断主题是否匹配订阅模式
+func match(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == "#" {
+			return true
+		}
+		if i >= len(topic) || (p != "*" && p != topic[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,335 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, topic string
+		want           bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.created", "orders.paid", false},
+		{"orders.created", "orders", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.eu.created", false},
+		{"*.created", "users.created", true},
+		{"orders.*.created", "orders.eu.created", true},
+		{"orders.*.created", "orders.eu.paid", false},
+		{"orders.#", "orders", true},
+		{"orders.#", "orders.created", true},
+		{"orders.#", "orders.eu.created", true},
+		{"orders.#", "users.created", false},
+		{"#", "anything.at.all", true},
+		{"*.#", "orders", true},
+	}
+	for _, tt := range tests {
+		p, err := parsePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("parsePattern(%q): %v", tt.pattern, err)
+		}
+		if got := match(p, strings.Split(tt.topic, ".")); got != tt.want {
+			t.Errorf("match(%q, %q) = %v，期望 %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidPatternAndTopic(t *testing.T) {
+	bus := New[int]()
+	defer bus.Close()
+	for _, pattern := range []string{"", "orders..created", "#.orders", "orders.cr*", "orders.#x"} {
+		if _, err := bus.Subscribe(pattern, Options{}); err == nil {
+			t.Errorf("Subscribe(%q) 应返回错误", pattern)
+		}
+	}
+	for _, topic := range []string{"", "orders.*", "orders.#"} {
+		if _, err := bus.Publish(context.Background(), topic, 1); err == nil {
+			t.Errorf("Publish(%q) 应返回错误", topic)
+		}
+	}
+}
+
+// receive 非阻塞地读出channel中现有的所有消息
+func receive[T any](sub *Subscription[T]) []T {
+	var got []T
+	for {
+		select {
+		case msg, ok := <-sub.C():
+			if !ok {
+				return got
+			}
+			got = append(got, msg.Payload)
+		default:
+			return got
+		}
+	}
+}
+
+func TestPublishRouting(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[string]()
+	defer bus.Close()
+	ctx := context.Background()
+
+	all, _ := bus.Subscribe("orders.#", Options{Buffer: 10})
+	one, _ := bus.Subscribe("orders.*", Options{Buffer: 10})
+	created, _ := bus.Subscribe("orders.*.created", Options{Buffer: 10})
+
+	for _, e := range []struct {
+		topic string
+		want  int
+	}{
+		{"orders", 1},
+		{"orders.paid", 2},
+		{"orders.eu.created", 2},
+		{"users.signup", 0},
+	} {
+		n, err := bus.Publish(ctx, e.topic, e.topic)
+		if err != nil || n != e.want {
+			t.Errorf("Publish(%q) = %d, %v，期望 %d", e.topic, n, err, e.want)
+		}
+	}
+
+	check := func(name string, sub *Subscription[string], want ...string) {
+		t.Helper()
+		if got := receive(sub); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s 收到 %v，期望 %v", name, got, want)
+		}
+	}
+	check("orders.#", all, "orders", "orders.paid", "orders.eu.created")
+	check("orders.*", one, "orders.paid")
+	check("orders.*.created", created, "orders.eu.created")
+}
+
+func TestDropNewest(t *testing.T) {
+	bus := New[int]()
+	defer bus.Close()
+	sub, _ := bus.Subscribe("t", Options{Buffer: 2, Policy: DropNewest})
+	for i := 1; i <= 5; i++ {
+		n, err := bus.Publish(context.Background(), "t", i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 1
+		if i > 2 {
+			want = 0 // 缓冲已满，新消息被丢弃
+		}
+		if n != want {
+			t.Errorf("第%d条放入了 %d 个订阅者，期望 %d", i, n, want)
+		}
+	}
+	if got := receive(sub); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("收到 %v，期望 [1 2]", got)
+	}
+	if sub.Dropped() != 3 {
+		t.Errorf("Dropped = %d，期望 3", sub.Dropped())
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	bus := New[int]()
+	defer bus.Close()
+	sub, _ := bus.Subscribe("t", Options{Buffer: 2, Policy: DropOldest})
+	for i := 1; i <= 5; i++ {
+		if n, err := bus.Publish(context.Background(), "t", i); n != 1 || err != nil {
+			t.Fatalf("Publish = %d, %v", n, err)
+		}
+	}
+	if got := receive(sub); len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Errorf("收到 %v，期望 [4 5]", got)
+	}
+	if sub.Dropped() != 3 {
+		t.Errorf("Dropped = %d，期望 3", sub.Dropped())
+	}
+}
+
+func TestDropPolicyMinimumBuffer(t *testing.T) {
+	bus := New[int]()
+	defer bus.Close()
+	sub, _ := bus.Subscribe("t", Options{Policy: DropOldest})
+	bus.Publish(context.Background(), "t", 1)
+	bus.Publish(context.Background(), "t", 2)
+	if got := receive(sub); len(got) != 1 || got[0] != 2 {
+		t.Errorf("收到 %v，期望 [2]", got)
+	}
+}
+
+// publishAsync 在后台发布，返回接收结果的channel
+func publishAsync[T any](ctx context.Context, bus *Bus[T], topic string, payload T) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		_, err := bus.Publish(ctx, topic, payload)
+		errc <- err
+	}()
+	return errc
+}
+
+// blocked 确认发布者仍在等待
+func blocked(t *testing.T, errc <-chan error) {
+	t.Helper()
+	select {
+	case err := <-errc:
+		t.Fatalf("发布者没有阻塞，返回 %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// result 等待发布者返回
+func result(t *testing.T, errc <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("发布者没有返回")
+		return nil
+	}
+}
+
+func TestBlockWaitsForReader(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	defer bus.Close()
+	sub, _ := bus.Subscribe("t", Options{Buffer: 1, Policy: Block})
+	bus.Publish(context.Background(), "t", 1)
+
+	errc := publishAsync(context.Background(), bus, "t", 2)
+	blocked(t, errc)
+	if v := (<-sub.C()).Payload; v != 1 {
+		t.Errorf("收到 %d，期望 1", v)
+	}
+	if err := result(t, errc); err != nil {
+		t.Errorf("Publish 返回 %v", err)
+	}
+	if v := (<-sub.C()).Payload; v != 2 {
+		t.Errorf("收到 %d，期望 2", v)
+	}
+}
+
+func TestBlockCancel(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	defer bus.Close()
+	bus.Subscribe("t", Options{Policy: Block})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := publishAsync(ctx, bus, "t", 1)
+	blocked(t, errc)
+	cancel()
+	if err := result(t, errc); !errors.Is(err, context.Canceled) {
+		t.Errorf("Publish 返回 %v，期望 context.Canceled", err)
+	}
+}
+
+// TestBlockDoesNotStallBus 一个阻塞的订阅者不应卡住 Subscribe、Unsubscribe 和其他主题的发布
+func TestBlockDoesNotStallBus(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	defer bus.Close()
+	bus.Subscribe("slow", Options{Policy: Block})
+
+	errc := publishAsync(context.Background(), bus, "slow", 1)
+	blocked(t, errc)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fast, err := bus.Subscribe("fast", Options{Buffer: 1})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if n, err := bus.Publish(context.Background(), "fast", 2); n != 1 || err != nil {
+			t.Errorf("Publish = %d, %v", n, err)
+		}
+		fast.Unsubscribe()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("阻塞的订阅者卡住了整个总线")
+	}
+
+	bus.Close()
+	if err := result(t, errc); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish 返回 %v，期望 ErrClosed", err)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	defer bus.Close()
+	sub, _ := bus.Subscribe("t", Options{Policy: Block})
+	other, _ := bus.Subscribe("t", Options{Buffer: 10})
+
+	// 取消订阅唤醒阻塞在它上面的发布者，发布者继续投递给其他订阅者
+	errc := publishAsync(context.Background(), bus, "t", 1)
+	blocked(t, errc)
+	sub.Unsubscribe()
+	if err := result(t, errc); err != nil {
+		t.Errorf("Publish 返回 %v", err)
+	}
+	if _, ok := <-sub.C(); ok {
+		t.Error("取消订阅后channel应被关闭")
+	}
+	sub.Unsubscribe() // 可以重复调用
+
+	if n, _ := bus.Publish(context.Background(), "t", 2); n != 1 {
+		t.Errorf("取消订阅后仍投递给了 %d 个订阅者", n)
+	}
+	if got := receive(other); len(got) != 2 {
+		t.Errorf("其他订阅者收到 %v", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	sub, _ := bus.Subscribe("t", Options{Buffer: 2})
+	bus.Publish(context.Background(), "t", 1)
+
+	bus.Close()
+	bus.Close() // 可以重复调用
+
+	// 关闭前的消息仍可读完，之后channel关闭
+	if msg, ok := <-sub.C(); !ok || msg.Payload != 1 {
+		t.Errorf("收到 %v, %v，期望剩余的消息", msg, ok)
+	}
+	if _, ok := <-sub.C(); ok {
+		t.Error("关闭后channel应被关闭")
+	}
+	sub.Unsubscribe() // 总线关闭后取消订阅不会panic
+
+	if _, err := bus.Publish(context.Background(), "t", 2); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish 返回 %v，期望 ErrClosed", err)
+	}
+	if _, err := bus.Subscribe("t", Options{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe 返回 %v，期望 ErrClosed", err)
+	}
+}
+
+// TestConcurrentPublishUnsubscribe 并发发布和取消订阅，配合 -race 检查不会向已关闭的channel发送
+func TestConcurrentPublishUnsubscribe(t *testing.T) {
+	leakcheck.Verify(t)
+	bus := New[int]()
+	defer bus.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 50; i++ {
+		sub, _ := bus.Subscribe("t", Options{Policy: Policy(i % 3)})
+		errc := publishAsync(ctx, bus, "t", i)
+		go sub.Unsubscribe()
+		if err := result(t, errc); err != nil {
+			t.Fatalf("Publish 返回 %v", err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 决定任务的运行时间
+type Schedule interface {
+	// Next 返回prev之后的下一次运行时间，返回零值表示不再运行
+	Next(prev time.Time) time.Time
+}
+
+// onceSchedule 只运行一次
+type onceSchedule struct {
+	delay time.Duration
+	fired bool
+}
+
+func (s *onceSchedule) Next(prev time.Time) time.Time {
+	if s.fired {
+		return time.Time{}
+	}
+	s.fired = true
+	return prev.Add(s.delay)
+}
+
+// intervalSchedule 固定间隔，以计划时间而不是实际运行时间为基准，不会累积漂移
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(prev time.Time) time.Time {
+	return prev.Add(time.Duration(s))
+}
+
+// CronSchedule 标准的5段cron表达式：分 时 日 月 星期
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64 // 位图，第i位表示取值i
+	domAny, dowAny                bool   // 日、星期是否为 *
+}
+
+// 各段的取值范围
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"分钟", 0, 59},
+	{"小时", 0, 23},
+	{"日", 1, 31},
+	{"月", 1, 12},
+	{"星期", 0, 7}, // 0和7都表示星期日
+}
+
+var cronMacros = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+// ParseCron 解析cron表达式，每段支持 *、数字、a-b 范围、/n 步长和逗号列表，
+// 如 "*/15 9-18 * * 1-5" 表示工作日9点到18点每15分钟；
+// 也支持 @hourly、@daily、@weekly、@monthly、@yearly
+func ParseCron(expr string) (*CronSchedule, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron表达式 %q 应有5段，实际为%d段", expr, len(fields))
+	}
+
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron表达式 %q 的%s段: %w", expr, cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+
+	// 星期7等同于星期0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+	return &CronSchedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("无效的步长 %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(a)
+			hi, err2 = strconv.Atoi(b)
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("无效的范围 %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("无效的取值 %q", rangePart)
+			}
+			lo, hi = n, n
+			if hasStep {
+				hi = max // "5/10" 表示从5开始每10个
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q 超出范围 %d-%d", rangePart, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func has(bits uint64, v int) bool { return bits&(1<<uint(v)) != 0 }
+
+// dayMatches 与标准cron一致：日和星期都有限制时，满足其一即可
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// Next 返回prev之后（按分钟取整）第一个匹配的时间，5年内找不到时返回零值
+func (c *CronSchedule) Next(prev time.Time) time.Time {
+	// 用time.Date按本地时间取整，Truncate按绝对时间取整，在半小时时区会出错
+	t := time.Date(prev.Year(), prev.Month(), prev.Day(), prev.Hour(), prev.Minute()+1, 0, 0, prev.Location())
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"time"
+
+	"go-learn/07_concurrency/clock"
+)
+
+// Harness 用假时钟驱动调度器，时间只在 Advance 时前进，任务的运行顺序是确定的
+//
+// 任务本身不应在假时钟上等待（如 clk.Sleep），否则 Advance 会一直等它结束。
+type Harness struct {
+	Clock     *clock.Fake
+	Scheduler *Scheduler
+}
+
+// NewHarness 创建从start开始的假时钟和使用它的调度器
+func NewHarness(start time.Time) *Harness {
+	fake := clock.NewFake(start)
+	return &Harness{Clock: fake, Scheduler: New(fake)}
+}
+
+// Advance 把时间推进d：逐个跳到期间到期的计划时间，每次都等到期的任务运行完，
+// 调度goroutine重新开始等待后，再跳到下一个
+func (h *Harness) Advance(d time.Duration) {
+	target := h.Clock.Now().Add(d)
+	for {
+		h.Scheduler.settle()
+		next, ok := h.Clock.NextDeadline()
+		if !ok || next.After(target) {
+			break
+		}
+		h.Clock.Advance(next.Sub(h.Clock.Now()))
+	}
+	h.Clock.Advance(target.Sub(h.Clock.Now()))
+	h.Scheduler.settle()
+}
+
+// Now 返回假时钟的当前时间
+func (h *Harness) Now() time.Time {
+	return h.Clock.Now()
+}
@@ -0,0 +1,324 @@
+// Package scheduler 进程内的任务调度器：延迟任务、固定间隔任务和cron任务
+//
+// 每个任务由一个goroutine按计划等待，到点后在新的goroutine中运行。
+// 调度器通过 clock.Clock 获取时间，换成假时钟就能确定性地检查调度结果（见 Harness）。
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go-learn/07_concurrency/clock"
+)
+
+var (
+	// ErrStopped 调度器停止后添加任务时返回
+	ErrStopped = errors.New("调度器已停止")
+	// ErrDuplicate 任务名重复时返回
+	ErrDuplicate = errors.New("任务名已存在")
+)
+
+// Job 被调度的任务，ctx在 Stop 超时后被取消
+type Job func(ctx context.Context) error
+
+// Options 任务选项
+type Options struct {
+	// Jitter 大于0时，每次运行时间随机推迟 [0, Jitter)，避免大量任务同时运行
+	Jitter time.Duration
+	// AllowOverlap 为false（默认）时，上一次还没运行完就跳过本次
+	AllowOverlap bool
+}
+
+// Stats 任务的运行统计
+type Stats struct {
+	Runs    int       // 已开始运行的次数
+	Skipped int       // 因上次未结束而跳过的次数
+	Failed  int       // 返回错误或panic的次数
+	Next    time.Time // 下一次计划运行时间，零值表示不再运行
+}
+
+// Scheduler 任务调度器
+type Scheduler struct {
+	clock clock.Clock
+
+	// OnError 任务返回错误或panic时调用（可选）
+	OnError func(name string, err error)
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	entries map[string]*entry
+	stopped bool
+	busy    int // 没有在等待计时器的调度goroutine数 + 正在运行的任务数，供 Harness 使用
+
+	loops   sync.WaitGroup // 调度goroutine
+	jobs    sync.WaitGroup // 正在运行的任务
+	jobCtx  context.Context
+	cancel  context.CancelFunc
+	randMu  sync.Mutex
+	randSrc *rand.Rand
+}
+
+type entry struct {
+	name     string
+	schedule Schedule
+	job      Job
+	opts     Options
+	remove   chan struct{}
+
+	// 以下字段由 Scheduler.mu 保护
+	running   bool
+	stats     Stats
+	waitUntil time.Time // 正在等待的计时器到期时间，没有等待时为零值
+}
+
+// New 创建调度器，clk为nil时使用真实时钟
+func New(clk clock.Clock) *Scheduler {
+	if clk == nil {
+		clk = clock.Real
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		clock:   clk,
+		entries: make(map[string]*entry),
+		jobCtx:  ctx,
+		cancel:  cancel,
+		randSrc: rand.New(rand.NewSource(clk.Now().UnixNano())),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// After 在delay之后运行一次
+func (s *Scheduler) After(name string, delay time.Duration, job Job) error {
+	return s.Add(name, &onceSchedule{delay: delay}, job, Options{})
+}
+
+// Every 每隔interval运行一次，第一次在interval之后
+func (s *Scheduler) Every(name string, interval time.Duration, job Job, opts Options) error {
+	if interval <= 0 {
+		return fmt.Errorf("任务 %s: 间隔必须大于0", name)
+	}
+	return s.Add(name, intervalSchedule(interval), job, opts)
+}
+
+// Cron 按cron表达式运行，表达式格式见 ParseCron
+func (s *Scheduler) Cron(name, expr string, job Job, opts Options) error {
+	sched, err := ParseCron(expr)
+	if err != nil {
+		return fmt.Errorf("任务 %s: %w", name, err)
+	}
+	return s.Add(name, sched, job, opts)
+}
+
+// Add 按自定义的 Schedule 添加任务
+func (s *Scheduler) Add(name string, sched Schedule, job Job, opts Options) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrStopped
+	}
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicate, name)
+	}
+
+	e := &entry{name: name, schedule: sched, job: job, opts: opts, remove: make(chan struct{})}
+	s.entries[name] = e
+	s.busy++
+	s.loops.Add(1)
+	go s.loop(e, s.clock.Now())
+	return nil
+}
+
+// Remove 移除任务，正在运行的那一次不受影响
+func (s *Scheduler) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if ok {
+		delete(s.entries, name)
+		close(e.remove)
+	}
+	return ok
+}
+
+// Stats 返回任务的运行统计
+func (s *Scheduler) Stats(name string) (Stats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok {
+		return Stats{}, false
+	}
+	return e.stats, true
+}
+
+// Stop 停止调度并等待正在运行的任务结束，类似 WaitGroup.Wait
+// ctx到期时取消任务的ctx并立即返回ctx的错误，不再等待：忽略ctx的任务可能仍在运行，
+// 与 http.Server.Shutdown 相同。停止后所有任务都被移除
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		// 和 Remove 一样先从列表删除再close，之后的 Remove 找不到任务，不会重复close
+		for name, e := range s.entries {
+			delete(s.entries, name)
+			close(e.remove)
+		}
+	}
+	s.mu.Unlock()
+	s.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// loop 一个任务的调度goroutine
+func (s *Scheduler) loop(e *entry, prev time.Time) {
+	defer s.loops.Done()
+	defer s.setBusy(-1)
+
+	for {
+		next := e.schedule.Next(prev)
+		s.mu.Lock()
+		e.stats.Next = next
+		s.mu.Unlock()
+		if next.IsZero() {
+			s.forget(e)
+			return
+		}
+
+		at := next.Add(s.jitter(e.opts.Jitter))
+		timer := s.clock.NewTimer(at.Sub(s.clock.Now()))
+
+		s.waiting(e, at)
+		select {
+		case <-timer.C():
+			s.waiting(e, time.Time{})
+		case <-e.remove:
+			timer.Stop()
+			s.waiting(e, time.Time{})
+			return
+		}
+
+		s.start(e)
+		prev = next // 以计划时间为基准，抖动和运行耗时都不会累积
+	}
+}
+
+// start 运行一次任务，上一次没结束且不允许重叠时跳过
+func (s *Scheduler) start(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	if e.running && !e.opts.AllowOverlap {
+		e.stats.Skipped++
+		return
+	}
+	e.running = true
+	e.stats.Runs++
+	s.busy++
+	s.jobs.Add(1)
+
+	go func() {
+		defer s.jobs.Done()
+		err := s.run(e)
+		if err != nil && s.OnError != nil {
+			s.OnError(e.name, err)
+		}
+
+		s.mu.Lock()
+		e.running = false
+		if err != nil {
+			e.stats.Failed++
+		}
+		s.busy--
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}()
+}
+
+// run 运行任务并把panic转换为错误
+func (s *Scheduler) run(e *entry) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("任务 %s panic: %v", e.name, v)
+		}
+	}()
+	return e.job(s.jobCtx)
+}
+
+// forget 一次性任务结束后从列表中移除
+func (s *Scheduler) forget(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries[e.name] == e {
+		delete(s.entries, e.name)
+	}
+}
+
+func (s *Scheduler) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	s.randMu.Lock()
+	defer s.randMu.Unlock()
+	return time.Duration(s.randSrc.Int63n(int64(max)))
+}
+
+func (s *Scheduler) setBusy(delta int) {
+	s.mu.Lock()
+	s.busy += delta
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// waiting 记录调度goroutine开始（until非零）或结束等待计时器
+func (s *Scheduler) waiting(e *entry, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until.IsZero() {
+		s.busy++
+	} else {
+		s.busy--
+	}
+	e.waitUntil = until
+	s.cond.Broadcast()
+}
+
+// settle 等待所有调度goroutine都在等待尚未到期的计时器、所有任务都已结束
+// 计时器已到期但goroutine还没被唤醒的也算作忙碌
+func (s *Scheduler) settle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.busy > 0 || s.due() {
+		s.cond.Wait()
+	}
+}
+
+func (s *Scheduler) due() bool {
+	now := s.clock.Now()
+	for _, e := range s.entries {
+		if !e.waitUntil.IsZero() && !e.waitUntil.After(now) {
+			return true
+		}
+	}
+	return false
+}
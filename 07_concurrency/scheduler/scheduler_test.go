@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-learn/07_concurrency/leakcheck"
+)
+
+// 2024-01-01 是星期一
+var monday = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+// recorder 记录任务每次运行时假时钟的时间
+type recorder struct {
+	h    *Harness
+	mu   sync.Mutex
+	runs []time.Time
+}
+
+func (r *recorder) job(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, r.h.Now())
+	return nil
+}
+
+func (r *recorder) times() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.runs...)
+}
+
+// check 比较运行时间，want是相对start的偏移
+func (r *recorder) check(t *testing.T, start time.Time, want ...time.Duration) {
+	t.Helper()
+	got := r.times()
+	if len(got) != len(want) {
+		t.Fatalf("运行了 %d 次 %v，期望 %d 次", len(got), got, len(want))
+	}
+	for i, d := range want {
+		if !got[i].Equal(start.Add(d)) {
+			t.Errorf("第%d次运行于 %v，期望 %v", i+1, got[i], start.Add(d))
+		}
+	}
+}
+
+// newHarness 创建测试用的Harness，测试结束时停止调度器并检查goroutine泄漏
+func newHarness(t *testing.T, start time.Time) (*Harness, *recorder) {
+	leakcheck.Verify(t)
+	h := NewHarness(start)
+	t.Cleanup(func() {
+		if err := h.Scheduler.Stop(context.Background()); err != nil {
+			t.Errorf("Stop: %v", err)
+		}
+	})
+	return h, &recorder{h: h}
+}
+
+func TestOneShot(t *testing.T) {
+	h, r := newHarness(t, monday)
+	if err := h.Scheduler.After("once", 10*time.Minute, r.job); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Advance(9 * time.Minute)
+	r.check(t, monday)
+	h.Advance(time.Hour)
+	r.check(t, monday, 10*time.Minute)
+
+	// 运行后一次性任务从列表中移除
+	if _, ok := h.Scheduler.Stats("once"); ok {
+		t.Error("一次性任务运行后应被移除")
+	}
+}
+
+func TestInterval(t *testing.T) {
+	h, r := newHarness(t, monday)
+	h.Scheduler.Every("tick", time.Hour, r.job, Options{})
+
+	h.Advance(3*time.Hour + 30*time.Minute)
+	r.check(t, monday, time.Hour, 2*time.Hour, 3*time.Hour)
+
+	st, ok := h.Scheduler.Stats("tick")
+	if !ok || st.Runs != 3 || !st.Next.Equal(monday.Add(4*time.Hour)) {
+		t.Errorf("Stats = %+v, %v", st, ok)
+	}
+}
+
+func TestCron(t *testing.T) {
+	h, r := newHarness(t, monday)
+	// 工作日9-11点的30分
+	if err := h.Scheduler.Cron("report", "30 9-11 * * 1-5", r.job, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Advance(26 * time.Hour) // 到第二天10点
+	r.check(t, monday, 90*time.Minute, 150*time.Minute, 210*time.Minute,
+		24*time.Hour+90*time.Minute)
+
+	if err := h.Scheduler.Cron("bad", "61 * * * *", r.job, Options{}); err == nil {
+		t.Error("无效的cron表达式应返回错误")
+	}
+}
+
+func TestCronSkipsWeekend(t *testing.T) {
+	friday := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	h, r := newHarness(t, friday)
+	h.Scheduler.Cron("daily", "0 9 * * 1-5", r.job, Options{})
+
+	h.Advance(4 * 24 * time.Hour) // 周五中午到下周二中午
+	r.check(t, friday, 3*24*time.Hour-3*time.Hour, 4*24*time.Hour-3*time.Hour)
+}
+
+func TestJitter(t *testing.T) {
+	h, r := newHarness(t, monday)
+	const jitter = 10 * time.Minute
+	h.Scheduler.Every("jittered", time.Hour, r.job, Options{Jitter: jitter})
+
+	h.Advance(5*time.Hour + jitter)
+	runs := r.times()
+	if len(runs) != 5 {
+		t.Fatalf("运行了 %d 次，期望 5 次", len(runs))
+	}
+	delayed := false
+	for i, at := range runs {
+		// 以计划时间为基准，抖动不会累积
+		planned := monday.Add(time.Duration(i+1) * time.Hour)
+		offset := at.Sub(planned)
+		if offset < 0 || offset >= jitter {
+			t.Errorf("第%d次运行于 %v，抖动 %v 超出 [0, %v)", i+1, at, offset, jitter)
+		}
+		if offset > 0 {
+			delayed = true
+		}
+	}
+	if !delayed {
+		t.Error("设置了抖动，但每次都准时运行")
+	}
+}
+
+func TestOverlapSkipped(t *testing.T) {
+	h, _ := newHarness(t, monday)
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	h.Scheduler.Every("slow", time.Minute, func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}, Options{})
+
+	// 任务会阻塞，不能用 Harness.Advance（它会等任务结束），直接推进假时钟：
+	// 等调度goroutine开始等待计时器，推进到期，再等它重新开始等待，这时本次已经处理完
+	for i := 0; i < 3; i++ {
+		h.Clock.BlockUntil(1)
+		h.Clock.Advance(time.Minute)
+	}
+	h.Clock.BlockUntil(1)
+	<-started
+
+	st, _ := h.Scheduler.Stats("slow")
+	if st.Runs != 1 || st.Skipped != 2 {
+		t.Errorf("Stats = %+v，期望运行1次、跳过2次", st)
+	}
+
+	// 上一次结束后恢复运行
+	close(release)
+	h.Advance(time.Minute)
+	if st, _ := h.Scheduler.Stats("slow"); st.Runs != 2 || st.Skipped != 2 {
+		t.Errorf("Stats = %+v，期望运行2次、跳过2次", st)
+	}
+}
+
+func TestAllowOverlap(t *testing.T) {
+	h, _ := newHarness(t, monday)
+	release := make(chan struct{})
+	h.Scheduler.Every("slow", time.Minute, func(ctx context.Context) error {
+		<-release
+		return nil
+	}, Options{AllowOverlap: true})
+
+	for i := 0; i < 3; i++ {
+		h.Clock.BlockUntil(1)
+		h.Clock.Advance(time.Minute)
+	}
+	h.Clock.BlockUntil(1)
+
+	st, _ := h.Scheduler.Stats("slow")
+	if st.Runs != 3 || st.Skipped != 0 {
+		t.Errorf("Stats = %+v，期望运行3次、不跳过", st)
+	}
+	close(release)
+}
+
+func TestErrors(t *testing.T) {
+	h, _ := newHarness(t, monday)
+	var mu sync.Mutex
+	var failures []string
+	h.Scheduler.OnError = func(name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, name)
+	}
+
+	h.Scheduler.Every("fail", time.Hour, func(ctx context.Context) error {
+		return errors.New("失败")
+	}, Options{})
+	h.Scheduler.Every("panic", time.Hour, func(ctx context.Context) error {
+		panic("崩溃")
+	}, Options{})
+
+	h.Advance(2 * time.Hour)
+	for _, name := range []string{"fail", "panic"} {
+		if st, _ := h.Scheduler.Stats(name); st.Runs != 2 || st.Failed != 2 {
+			t.Errorf("%s: Stats = %+v，期望运行2次、失败2次", name, st)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) != 4 {
+		t.Errorf("OnError 被调用 %d 次 %v，期望 4 次", len(failures), failures)
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	h, r := newHarness(t, monday)
+	s := h.Scheduler
+	s.Every("job", time.Hour, r.job, Options{})
+	if err := s.Every("job", time.Hour, r.job, Options{}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("重复的任务名返回 %v，期望 ErrDuplicate", err)
+	}
+	if err := s.Every("zero", 0, r.job, Options{}); err == nil {
+		t.Error("间隔为0应返回错误")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	h, r := newHarness(t, monday)
+	s := h.Scheduler
+	s.Every("tick", time.Hour, r.job, Options{})
+
+	h.Advance(time.Hour)
+	if !s.Remove("tick") {
+		t.Fatal("Remove 应返回true")
+	}
+	if s.Remove("tick") {
+		t.Error("重复 Remove 应返回false")
+	}
+	h.Advance(5 * time.Hour)
+	r.check(t, monday, time.Hour)
+}
+
+func TestStop(t *testing.T) {
+	leakcheck.Verify(t)
+	h := NewHarness(monday)
+	s := h.Scheduler
+	r := &recorder{h: h}
+	s.Every("tick", time.Hour, r.job, Options{})
+	s.After("once", time.Minute, r.job)
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 停止后 Remove 不会重复close而panic
+	if s.Remove("tick") || s.Remove("once") {
+		t.Error("停止后任务已被移除，Remove 应返回false")
+	}
+	if err := s.After("late", time.Minute, r.job); !errors.Is(err, ErrStopped) {
+		t.Errorf("停止后添加任务返回 %v，期望 ErrStopped", err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Errorf("重复 Stop 返回 %v", err)
+	}
+	h.Clock.Advance(time.Hour)
+	r.check(t, monday)
+}
+
+func TestStopTimeoutCancelsJobs(t *testing.T) {
+	leakcheck.Verify(t)
+	h := NewHarness(monday)
+	s := h.Scheduler
+	started := make(chan struct{})
+	s.After("slow", time.Minute, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done() // 只有Stop超时取消ctx后才结束
+		return ctx.Err()
+	})
+	h.Clock.BlockUntil(1)
+	h.Clock.Advance(time.Minute)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop 返回 %v，期望 DeadlineExceeded", err)
+	}
+}
+
+// TestStopTimeoutIgnoredCtx 任务不理会ctx时，Stop 仍在ctx到期后按时返回
+func TestStopTimeoutIgnoredCtx(t *testing.T) {
+	leakcheck.Verify(t)
+	h := NewHarness(monday)
+	s := h.Scheduler
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release) // 测试结束时放行，避免泄漏检查失败
+	s.After("stubborn", time.Minute, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	h.Clock.BlockUntil(1)
+	h.Clock.Advance(time.Minute)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- s.Stop(ctx) }()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Stop 返回 %v，期望 DeadlineExceeded", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("任务忽略ctx时 Stop 没有在超时后返回")
+	}
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-learn/10_practice/i18n"
+)
+
+// fieldKey 返回结构体字段在配置文件中的键名（json标签）
+func fieldKey(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name
+}
+
+// assign 把解析得到的树写入cfg，文件中出现的值覆盖默认值
+// 未知的键和类型不匹配都会报告为 *FieldError
+func assign(cfg *Config, tree map[string]any) error {
+	var errs []error
+	assignStruct(reflect.ValueOf(cfg).Elem(), tree, "", &errs)
+	return errors.Join(errs...)
+}
+
+func assignStruct(v reflect.Value, tree map[string]any, prefix string, errs *[]error) {
+	fields := make(map[string]reflect.Value)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fields[fieldKey(t.Field(i))] = v.Field(i)
+	}
+
+	// 按键名排序，保证错误信息的顺序稳定
+	keys := make([]string, 0, len(tree))
+	for k := range tree {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := prefix + k
+		field, ok := fields[k]
+		if !ok {
+			*errs = append(*errs, &FieldError{Key: key, Msg: i18n.T("config.unknown_key")})
+			continue
+		}
+		assignValue(field, tree[k], key, errs)
+	}
+}
+
+func assignValue(v reflect.Value, raw any, key string, errs *[]error) {
+	bad := func(want string) {
+		*errs = append(*errs, &FieldError{Key: key, Msg: wrongType(want, describe(raw))})
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			bad("config.type.string")
+			return
+		}
+		v.SetString(s)
+
+	case reflect.Int:
+		f, ok := raw.(float64)
+		if !ok || f != math.Trunc(f) {
+			bad("config.type.int")
+			return
+		}
+		v.SetInt(int64(f))
+
+	case reflect.Float64:
+		f, ok := raw.(float64)
+		if !ok {
+			bad("config.type.number")
+			return
+		}
+		v.SetFloat(f)
+
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			bad("config.type.bool")
+			return
+		}
+		v.SetBool(b)
+
+	case reflect.Struct:
+		m, ok := raw.(map[string]any)
+		if !ok {
+			bad("config.type.table")
+			return
+		}
+		assignStruct(v, m, key+".", errs)
+
+	case reflect.Slice:
+		list, ok := raw.([]any)
+		if !ok {
+			bad("config.type.array")
+			return
+		}
+		// 文件中给出的列表整体替换默认列表
+		s := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, item := range list {
+			assignValue(s.Index(i), item, fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+		v.Set(s)
+	}
+}
+
+// wrongType 返回类型不符的提示，want为类型名称的消息键
+func wrongType(want, got string) string {
+	return i18n.T("config.wrong_type", i18n.T(want), got)
+}
+
+// describe 以便于阅读的方式描述解析出来的值
+func describe(raw any) string {
+	switch x := raw.(type) {
+	case string:
+		return strconv.Quote(x)
+	case map[string]any:
+		return i18n.T("config.type.table")
+	case []any:
+		return i18n.T("config.type.array")
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(raw)
+}
+
+// EnvName 返回配置项对应的环境变量名，如 guess.max_attempts -> PRACTICE_GUESS_MAX_ATTEMPTS
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyEnv 遍历所有标量和字符串列表配置项，存在对应环境变量时覆盖
+// 结构体列表（如 students）只能在配置文件中设置
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	walkEnv(reflect.ValueOf(cfg).Elem(), "", lookup, &errs)
+	return errors.Join(errs...)
+}
+
+func walkEnv(v reflect.Value, prefix string, lookup func(string) (string, bool), errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := prefix + fieldKey(t.Field(i))
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			walkEnv(field, key+".", lookup, errs)
+			continue
+		}
+
+		name := EnvName(key)
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(field, raw); err != nil {
+			*errs = append(*errs, &FieldError{Key: key, Msg: i18n.T("config.env", name, err)})
+		}
+	}
+}
+
+// setFromString 把环境变量的字符串值写入字段，列表以逗号分隔
+func setFromString(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New(wrongType("config.type.int", strconv.Quote(raw)))
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New(wrongType("config.type.number", strconv.Quote(raw)))
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New(wrongType("config.type.bool", strconv.Quote(raw)))
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return i18n.Error("config.file_only")
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package config 加载实战练习的配置：默认值 -> 配置文件(JSON或类TOML) -> 环境变量覆盖 -> 校验
+//
+// 配置项用点号分隔的路径表示，如 guess.max_attempts；
+// 对应的环境变量为 PRACTICE_GUESS_MAX_ATTEMPTS，列表用逗号分隔
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-learn/10_practice/guess"
+	"go-learn/10_practice/i18n"
+)
+
+// EnvPrefix 环境变量前缀
+const EnvPrefix = "PRACTICE_"
+
+// Config 实战练习的全部配置
+type Config struct {
+	Guess      GuessConfig      `json:"guess"`
+	Downloader DownloaderConfig `json:"downloader"`
+	Calculator CalculatorConfig `json:"calculator"`
+	Students   []StudentConfig  `json:"students"`
+}
+
+// GuessConfig 猜数字游戏配置
+type GuessConfig struct {
+	Difficulty  string `json:"difficulty"`   // 默认难度: easy、normal 或 hard
+	MaxAttempts int    `json:"max_attempts"` // 覆盖默认难度的可猜次数，0表示使用难度自带的次数
+	Leaderboard string `json:"leaderboard"`  // 排行榜文件路径，为空时使用用户配置目录
+}
+
+// DownloaderConfig 并发下载器配置
+type DownloaderConfig struct {
+	URLs              []string `json:"urls"`
+	MaxPerHost        int      `json:"max_per_host"`
+	RequestsPerSecond float64  `json:"requests_per_second"`
+	BytesPerSecond    float64  `json:"bytes_per_second"`
+}
+
+// CalculatorConfig 计算器演示配置
+type CalculatorConfig struct {
+	Expressions []string `json:"expressions"`
+}
+
+// StudentConfig 学生管理系统演示中预置的学生
+type StudentConfig struct {
+	Name  string  `json:"name"`
+	Age   int     `json:"age"`
+	Grade float64 `json:"grade"`
+}
+
+// Default 返回默认配置，与练习中原来写死的值一致
+func Default() Config {
+	return Config{
+		Guess: GuessConfig{
+			Difficulty: "normal",
+		},
+		Downloader: DownloaderConfig{
+			URLs: []string{
+				"https://example.com/file1.zip",
+				"https://example.com/file2.zip",
+				"https://example.com/file3.zip",
+				"https://example.com/file4.zip",
+			},
+			MaxPerHost:        2,
+			RequestsPerSecond: 4,
+			BytesPerSecond:    512 * 1024,
+		},
+		Calculator: CalculatorConfig{
+			Expressions: []string{
+				"10 + 5",
+				"20 - 8",
+				"6 * 7",
+				"15 / 3",
+				"10 / 0",  // 错误示例
+				"abc + 5", // 错误示例
+			},
+		},
+		Students: []StudentConfig{
+			{Name: "张三", Age: 20, Grade: 85.5},
+			{Name: "李四", Age: 19, Grade: 92.0},
+			{Name: "王五", Age: 21, Grade: 78.5},
+			{Name: "赵六", Age: 20, Grade: 88.0},
+		},
+	}
+}
+
+// FieldError 指向具体配置项的错误
+type FieldError struct {
+	File string // 出错的配置文件，来自环境变量或校验时为空
+	Key  string // 如 guess.max_attempts、students[2].grade
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	if e.File != "" {
+		return i18n.T("config.field_in_file", e.File, e.Key, e.Msg)
+	}
+	return i18n.T("config.field", e.Key, e.Msg)
+}
+
+// Load 在默认配置的基础上读取path，按扩展名选择格式：
+// .json 为JSON，其余（如 .toml、.conf）按类TOML格式解析
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	var tree map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &tree)
+	} else {
+		tree, err = parseTOML(string(data))
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := assign(&cfg, tree); err != nil {
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			e.(*FieldError).File = path
+		}
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// ApplyEnv 用环境变量覆盖配置，lookup通常为 os.LookupEnv
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	return applyEnv(c, lookup)
+}
+
+// Validate 校验配置，返回所有问题（每个都是 *FieldError）
+func (c Config) Validate() error {
+	var errs []error
+	bad := func(key, msgKey string, args ...any) {
+		errs = append(errs, &FieldError{Key: key, Msg: i18n.T(msgKey, args...)})
+	}
+	// 比较运算对NaN总是返回false，必须单独排除NaN和无穷大
+	nonNegative := func(key string, f float64) {
+		switch {
+		case math.IsNaN(f) || math.IsInf(f, 0):
+			bad(key, "config.not_finite", f)
+		case f < 0:
+			bad(key, "config.negative", f)
+		}
+	}
+
+	if _, ok := guess.DifficultyByName(c.Guess.Difficulty); !ok {
+		bad("guess.difficulty", "config.bad_difficulty", c.Guess.Difficulty)
+	}
+	if c.Guess.MaxAttempts < 0 {
+		bad("guess.max_attempts", "config.negative", c.Guess.MaxAttempts)
+	}
+
+	if len(c.Downloader.URLs) == 0 {
+		bad("downloader.urls", "config.no_urls")
+	}
+	for i, raw := range c.Downloader.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			bad(fmt.Sprintf("downloader.urls[%d]", i), "config.bad_url", raw)
+		}
+	}
+	if c.Downloader.MaxPerHost < 0 {
+		bad("downloader.max_per_host", "config.negative", c.Downloader.MaxPerHost)
+	}
+	nonNegative("downloader.requests_per_second", c.Downloader.RequestsPerSecond)
+	nonNegative("downloader.bytes_per_second", c.Downloader.BytesPerSecond)
+
+	for i, expr := range c.Calculator.Expressions {
+		if strings.TrimSpace(expr) == "" {
+			bad(fmt.Sprintf("calculator.expressions[%d]", i), "config.empty_expression")
+		}
+	}
+
+	for i, s := range c.Students {
+		key := fmt.Sprintf("students[%d]", i)
+		if strings.TrimSpace(s.Name) == "" {
+			bad(key+".name", "config.empty_name")
+		}
+		if s.Age <= 0 || s.Age > 150 {
+			bad(key+".age", "config.out_of_range", 1, 150, s.Age)
+		}
+		// !(a && b) 的写法同时拒绝NaN
+		if !(s.Grade >= 0 && s.Grade <= 100) {
+			bad(key+".grade", "config.out_of_range", 0, 100, s.Grade)
+		}
+	}
+
+	return errors.Join(errs...)
+}
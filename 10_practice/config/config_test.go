@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-learn/10_practice/i18n"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// fieldErrors 把 errors.Join 的结果展开为 *FieldError 列表
+func fieldErrors(t *testing.T, err error) []*FieldError {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var list []*FieldError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fe *FieldError
+		if !errors.As(e, &fe) {
+			t.Fatalf("%v 不是 *FieldError", e)
+		}
+		list = append(list, fe)
+	}
+	return list
+}
+
+func keys(errs []*FieldError) []string {
+	var ks []string
+	for _, e := range errs {
+		ks = append(ks, e.Key)
+	}
+	return ks
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("默认配置校验失败: %v", err)
+	}
+}
+
+func TestExampleConfig(t *testing.T) {
+	cfg, err := Load("../practice.example.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("示例配置校验失败: %v", err)
+	}
+	if len(cfg.Downloader.URLs) != 3 || cfg.Students[1].Name != "李四" {
+		t.Errorf("示例配置读取错误: %+v", cfg)
+	}
+}
+
+func TestLoadTOMLAndJSON(t *testing.T) {
+	toml := writeFile(t, "c.toml", `
+[guess]
+difficulty = "hard"   # 注释
+[downloader]
+urls = [
+  "https://a.example/1",
+  "https://b.example/#2",
+]
+bytes_per_second = 1_024
+[[students]]
+name = "甲"
+age = 18
+grade = 90
+`)
+	json := writeFile(t, "c.json", `{
+  "guess": {"difficulty": "hard"},
+  "downloader": {"urls": ["https://a.example/1", "https://b.example/#2"], "bytes_per_second": 1024},
+  "students": [{"name": "甲", "age": 18, "grade": 90}]
+}`)
+
+	a, err := Load(toml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("TOML与JSON结果不同:\n%+v\n%+v", a, b)
+	}
+	if a.Guess.Difficulty != "hard" || a.Downloader.BytesPerSecond != 1024 ||
+		a.Downloader.MaxPerHost != 2 || len(a.Students) != 1 {
+		t.Errorf("读取结果错误: %+v", a)
+	}
+}
+
+func TestLoadFieldErrors(t *testing.T) {
+	path := writeFile(t, "bad.toml", `
+[guess]
+max_attempts = 2.5
+colour = "red"
+[downloader]
+urls = "https://a.example"
+`)
+	_, err := Load(path)
+	errs := fieldErrors(t, err)
+	want := []string{"downloader.urls", "guess.colour", "guess.max_attempts"}
+	if !reflect.DeepEqual(keys(errs), want) {
+		t.Fatalf("出错的配置项 %v，期望 %v", keys(errs), want)
+	}
+	for _, e := range errs {
+		if e.File != path || !strings.HasPrefix(e.Error(), path+": ") {
+			t.Errorf("错误信息中缺少文件名: %v", e)
+		}
+	}
+}
+
+func TestTOMLSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name, text string
+		line       int
+	}{
+		{"缺少等号", "[guess]\ndifficulty\n", 2},
+		{"重复的表", "[guess]\n[guess]\n", 2},
+		{"重复的键", "[guess]\na = 1\na = 2\n", 3},
+		{"未闭合的字符串", `a = "abc`, 1},
+		{"数组缺少逗号", `a = ["x" "y"]`, 1},
+		{"无法识别的值", "a = yes", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML(tt.text)
+			if err == nil {
+				t.Fatal("期望返回错误")
+			}
+			if want := i18n.T("config.toml.line", tt.line, ""); !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("错误 %q 应以 %q 开头", err, want)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"PRACTICE_GUESS_MAX_ATTEMPTS":      "5",
+		"PRACTICE_DOWNLOADER_URLS":         "https://a.example/1, https://b.example/2,",
+		"PRACTICE_DOWNLOADER_MAX_PER_HOST": "x",
+		"PRACTICE_STUDENTS":                "张三",
+	}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	cfg := Default()
+	errs := fieldErrors(t, cfg.ApplyEnv(lookup))
+	if want := []string{"downloader.max_per_host", "students"}; !reflect.DeepEqual(keys(errs), want) {
+		t.Errorf("出错的配置项 %v，期望 %v", keys(errs), want)
+	}
+	if cfg.Guess.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d，期望 5", cfg.Guess.MaxAttempts)
+	}
+	if want := []string{"https://a.example/1", "https://b.example/2"}; !reflect.DeepEqual(cfg.Downloader.URLs, want) {
+		t.Errorf("URLs = %q，期望 %q", cfg.Downloader.URLs, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		value string
+		bad   string
+	}{
+		{"NaN速率", "PRACTICE_DOWNLOADER_BYTES_PER_SECOND", "NaN", "downloader.bytes_per_second"},
+		{"无穷大速率", "PRACTICE_DOWNLOADER_REQUESTS_PER_SECOND", "+Inf", "downloader.requests_per_second"},
+		{"负数速率", "PRACTICE_DOWNLOADER_BYTES_PER_SECOND", "-1", "downloader.bytes_per_second"},
+		{"未知难度", "PRACTICE_GUESS_DIFFICULTY", "extreme", "guess.difficulty"},
+		{"负数次数", "PRACTICE_GUESS_MAX_ATTEMPTS", "-3", "guess.max_attempts"},
+		{"非http地址", "PRACTICE_DOWNLOADER_URLS", "ftp://a.example/1", "downloader.urls[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			lookup := func(k string) (string, bool) { return tt.value, k == tt.env }
+			if err := cfg.ApplyEnv(lookup); err != nil {
+				t.Fatal(err)
+			}
+			got := keys(fieldErrors(t, cfg.Validate()))
+			if want := []string{tt.bad}; !reflect.DeepEqual(got, want) {
+				t.Errorf("出错的配置项 %v，期望 %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateStudents(t *testing.T) {
+	cfg := Default()
+	cfg.Students = []StudentConfig{
+		{Name: " ", Age: 0, Grade: 101},
+		{Name: "甲", Age: 20, Grade: 90},
+	}
+	cfg.Calculator.Expressions = []string{"1+1", ""}
+	got := keys(fieldErrors(t, cfg.Validate()))
+	want := []string{"calculator.expressions[1]", "students[0].name", "students[0].age", "students[0].grade"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("出错的配置项 %v，期望 %v", got, want)
+	}
+}
+
+func TestErrorsAreLocalized(t *testing.T) {
+	defer i18n.SetLocale(i18n.Current())
+	cfg := Default()
+	cfg.Guess.Difficulty = "extreme"
+
+	i18n.SetLocale(i18n.English)
+	if msg := cfg.Validate().Error(); msg != `setting guess.difficulty: must be easy, normal or hard, got "extreme"` {
+		t.Errorf("英文错误信息 = %q", msg)
+	}
+	i18n.SetLocale(i18n.Chinese)
+	if msg := cfg.Validate().Error(); !strings.HasPrefix(msg, "配置项 guess.difficulty: ") {
+		t.Errorf("中文错误信息 = %q", msg)
+	}
+}
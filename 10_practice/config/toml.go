@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"go-learn/10_practice/i18n"
+)
+
+// lineError 返回带行号的解析错误
+func lineError(lineNo int, msg string) error {
+	return errors.New(i18n.T("config.toml.line", lineNo, msg))
+}
+
+// syntaxError 返回本地化的语法错误，key为消息键
+func syntaxError(key string, args ...any) error {
+	return errors.New(i18n.T(key, args...))
+}
+
+// parseTOML 解析类TOML格式的一个子集：
+//
+//	# 注释
+//	[guess]
+//	max_attempts = 10
+//	[downloader]
+//	urls = ["https://a.example/1", "https://b.example/2"]
+//	[[students]]
+//	name = "张三"
+//
+// 值支持字符串、整数、浮点数、布尔值和数组，数组可以跨多行
+func parseTOML(text string) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "[["):
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "[["), "]]"))
+			if name == "" || !strings.HasSuffix(line, "]]") {
+				return nil, lineError(lineNo, i18n.T("config.toml.bad_array_table", line))
+			}
+			list, _ := root[name].([]any)
+			if _, exists := root[name]; exists && list == nil {
+				return nil, lineError(lineNo, i18n.T("config.toml.type_conflict", name))
+			}
+			current = make(map[string]any)
+			root[name] = append(list, current)
+
+		case strings.HasPrefix(line, "["):
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+			if name == "" || !strings.HasSuffix(line, "]") {
+				return nil, lineError(lineNo, i18n.T("config.toml.bad_table", line))
+			}
+			if _, exists := root[name]; exists {
+				return nil, lineError(lineNo, i18n.T("config.toml.duplicate_table", name))
+			}
+			current = make(map[string]any)
+			root[name] = current
+
+		default:
+			key, raw, ok := strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return nil, lineError(lineNo, i18n.T("config.toml.want_key_value", line))
+			}
+			// 数组可以跨多行，一直读到方括号配对为止
+			raw = strings.TrimSpace(raw)
+			for strings.HasPrefix(raw, "[") && bracketDepth(raw) > 0 && i+1 < len(lines) {
+				i++
+				raw += " " + strings.TrimSpace(stripComment(lines[i]))
+			}
+			if _, exists := current[key]; exists {
+				return nil, lineError(lineNo, i18n.T("config.toml.duplicate_key", key))
+			}
+			value, err := parseValue(raw)
+			if err != nil {
+				return nil, lineError(lineNo, key+": "+err.Error())
+			}
+			current[key] = value
+		}
+	}
+
+	return root, nil
+}
+
+// bracketDepth 返回s中未配对的左方括号数量，忽略字符串中的方括号
+func bracketDepth(s string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '[':
+			if !inString {
+				depth++
+			}
+		case ']':
+			if !inString {
+				depth--
+			}
+		}
+	}
+	return depth
+}
+
+// stripComment 去掉不在字符串中的 # 注释
+func stripComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '#':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// parseValue 解析一个值，数字统一为float64，与encoding/json的结果一致
+func parseValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, syntaxError("config.toml.missing_value")
+	case raw == "true":
+		return true, nil
+	case raw == "false":
+		return false, nil
+	case strings.HasPrefix(raw, `"`):
+		s, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, syntaxError("config.toml.bad_string", raw)
+		}
+		return s, nil
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, syntaxError("config.toml.unclosed_array", raw)
+		}
+		return parseArray(strings.TrimSpace(raw[1 : len(raw)-1]))
+	}
+
+	f, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64)
+	if err != nil {
+		return nil, syntaxError("config.toml.bad_value", raw)
+	}
+	return f, nil
+}
+
+// parseArray 解析数组的内容（不含方括号），元素以逗号分隔，允许末尾逗号
+func parseArray(body string) ([]any, error) {
+	items := []any{}
+	for body != "" {
+		var elem string
+		if strings.HasPrefix(body, `"`) {
+			end := closingQuote(body)
+			if end < 0 {
+				return nil, syntaxError("config.toml.unclosed_string", body)
+			}
+			elem, body = body[:end+1], body[end+1:]
+		} else {
+			elem, body, _ = strings.Cut(body, ",")
+			body = "," + body
+		}
+
+		v, err := parseValue(strings.TrimSpace(elem))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+
+		body = strings.TrimSpace(body)
+		if body == "," {
+			break
+		}
+		if body != "" && !strings.HasPrefix(body, ",") {
+			return nil, syntaxError("config.toml.missing_comma", body)
+		}
+		body = strings.TrimSpace(strings.TrimPrefix(body, ","))
+	}
+	return items, nil
+}
+
+// closingQuote 返回与s开头引号配对的结束引号下标
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,181 @@
+// Package downloader 提供支持按主机限制并发和令牌桶限速的并发下载器
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go-learn/10_practice/i18n"
+)
+
+// Fetcher 打开一个URL对应的数据流
+type Fetcher func(ctx context.Context, rawURL string) (io.ReadCloser, error)
+
+// Config 下载器配置，零值表示不做任何限制
+type Config struct {
+	MaxPerHost        int     // 每个主机的最大并发连接数
+	RequestsPerSecond float64 // 所有goroutine共享的请求速率
+	BytesPerSecond    float64 // 所有goroutine共享的下载带宽
+}
+
+// Result 单个URL的下载结果
+type Result struct {
+	ID       int
+	URL      string
+	Bytes    int64
+	Duration time.Duration
+	Err      error
+}
+
+// Downloader 并发下载器
+type Downloader struct {
+	fetch    Fetcher
+	hosts    *hostLimiter
+	requests *TokenBucket
+	bytes    *TokenBucket
+	chunk    int
+
+	// OnStart 在获得连接名额、开始下载时调用（可选）
+	OnStart func(id int, rawURL string)
+
+	// Logger 诊断日志，New 中默认为 slog.Default()
+	Logger *slog.Logger
+}
+
+// New 创建下载器，fetch为nil时使用http.DefaultClient
+func New(cfg Config, fetch Fetcher) *Downloader {
+	if fetch == nil {
+		fetch = HTTPFetcher(http.DefaultClient)
+	}
+
+	chunk := 32 * 1024
+	if cfg.BytesPerSecond > 0 && cfg.BytesPerSecond < float64(chunk) {
+		// 低于1字节/秒时也至少读1字节，大小为0的缓冲区会让读取一直原地空转
+		chunk = max(int(cfg.BytesPerSecond), 1)
+	}
+
+	return &Downloader{
+		fetch:    fetch,
+		hosts:    newHostLimiter(cfg.MaxPerHost),
+		requests: NewTokenBucket(cfg.RequestsPerSecond, 1),
+		bytes:    NewTokenBucket(cfg.BytesPerSecond, chunk),
+		chunk:    chunk,
+		Logger:   slog.Default().With("component", "downloader"),
+	}
+}
+
+// HTTPFetcher 使用给定的http.Client发起GET请求
+func HTTPFetcher(client *http.Client) Fetcher {
+	return func(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, errors.New(i18n.T("downloader.bad_status", resp.StatusCode))
+		}
+		return resp.Body, nil
+	}
+}
+
+// Download 下载单个URL并丢弃内容，返回下载的字节数
+func (d *Downloader) Download(ctx context.Context, id int, rawURL string) (int64, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", i18n.T("downloader.bad_url"), err)
+	}
+
+	log := d.Logger.With("id", id, "host", u.Host)
+	log.Debug("等待连接名额", "url", rawURL)
+
+	release, err := d.hosts.acquire(ctx, u.Host)
+	if err != nil {
+		log.Warn("等待连接名额时取消", "err", err)
+		return 0, err
+	}
+	defer release()
+
+	if err := d.requests.Wait(ctx); err != nil {
+		log.Warn("等待请求令牌时取消", "err", err)
+		return 0, err
+	}
+
+	if d.OnStart != nil {
+		d.OnStart(id, rawURL)
+	}
+
+	start := time.Now()
+	body, err := d.fetch(ctx, rawURL)
+	if err != nil {
+		log.Warn("请求失败", "url", rawURL, "err", err)
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := d.copy(ctx, body)
+	if err != nil {
+		log.Warn("下载中断", "url", rawURL, "bytes", n, "err", err)
+		return n, err
+	}
+	log.Info("下载完成", "url", rawURL, "bytes", n, "duration", time.Since(start))
+	return n, nil
+}
+
+// copy 按块读取数据，每块读取前先从带宽令牌桶中取令牌
+func (d *Downloader) copy(ctx context.Context, r io.Reader) (int64, error) {
+	buf := make([]byte, d.chunk)
+	var total int64
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			total += int64(n)
+			if werr := d.bytes.WaitN(ctx, n); werr != nil {
+				return total, werr
+			}
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// DownloadAll 并发下载所有URL，结果顺序与urls一致
+func (d *Downloader) DownloadAll(ctx context.Context, urls []string) []Result {
+	results := make([]Result, len(urls))
+	var wg sync.WaitGroup
+
+	for i, rawURL := range urls {
+		wg.Add(1)
+		go func(i int, rawURL string) {
+			defer wg.Done()
+			start := time.Now()
+			n, err := d.Download(ctx, i+1, rawURL)
+			results[i] = Result{
+				ID:       i + 1,
+				URL:      rawURL,
+				Bytes:    n,
+				Duration: time.Since(start),
+				Err:      err,
+			}
+		}(i, rawURL)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,170 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newServer 返回一个本地服务器：每个请求返回size字节，处理耗时delay，并记录最大并发数
+func newServer(t *testing.T, size int, delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var inflight, peak atomic.Int32
+	body := make([]byte, size)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &peak
+}
+
+func urls(srv *httptest.Server, n int) []string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = srv.URL + "/file" + strconv.Itoa(i)
+	}
+	return list
+}
+
+// within 检查实际耗时在 [want*0.9, want*1.5+100ms] 之内，给调度抖动留出余量
+func within(t *testing.T, name string, got, want time.Duration) {
+	t.Helper()
+	if got < want*9/10 || got > want*3/2+100*time.Millisecond {
+		t.Errorf("%s: 耗时 %v，期望约 %v", name, got, want)
+	}
+}
+
+func TestRequestRate(t *testing.T) {
+	srv, _ := newServer(t, 10, 0)
+	d := New(Config{RequestsPerSecond: 20}, HTTPFetcher(srv.Client()))
+
+	start := time.Now()
+	results := d.DownloadAll(context.Background(), urls(srv, 11))
+	elapsed := time.Since(start)
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("下载 %s 失败: %v", r.URL, r.Err)
+		}
+	}
+	// 桶容量为1：第一个请求立即发出，其余10个每个间隔50ms
+	within(t, "11个请求 @20/s", elapsed, 500*time.Millisecond)
+}
+
+func TestByteRate(t *testing.T) {
+	const size = 64 * 1024
+	srv, _ := newServer(t, size, 0)
+	d := New(Config{BytesPerSecond: 256 * 1024}, HTTPFetcher(srv.Client()))
+
+	start := time.Now()
+	results := d.DownloadAll(context.Background(), urls(srv, 2))
+	elapsed := time.Since(start)
+
+	var total int64
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("下载 %s 失败: %v", r.URL, r.Err)
+		}
+		total += r.Bytes
+	}
+	if total != 2*size {
+		t.Fatalf("共下载 %d 字节，期望 %d", total, 2*size)
+	}
+	// 初始的一桶（32KB）不用等待，剩下的96KB按256KB/s下载
+	within(t, "128KB @256KB/s", elapsed, 375*time.Millisecond)
+}
+
+// TestByteRateBelowOne 带宽低于1字节/秒时每块至少1字节，不会因为缓冲区为空而卡住
+func TestByteRateBelowOne(t *testing.T) {
+	srv, _ := newServer(t, 1, 0)
+	d := New(Config{BytesPerSecond: 0.5}, HTTPFetcher(srv.Client()))
+
+	done := make(chan Result, 1)
+	go func() { done <- d.DownloadAll(context.Background(), urls(srv, 1))[0] }()
+	select {
+	case r := <-done:
+		// 初始的一桶够下载这1个字节
+		if r.Err != nil || r.Bytes != 1 {
+			t.Errorf("下载结果 %d 字节, %v，期望 1 字节", r.Bytes, r.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("带宽低于1字节/秒时下载卡住了")
+	}
+}
+
+func TestMaxPerHost(t *testing.T) {
+	srv, peak := newServer(t, 10, 50*time.Millisecond)
+	d := New(Config{MaxPerHost: 2}, HTTPFetcher(srv.Client()))
+
+	start := time.Now()
+	d.DownloadAll(context.Background(), urls(srv, 6))
+	elapsed := time.Since(start)
+
+	if got := peak.Load(); got != 2 {
+		t.Errorf("最大并发连接数为 %d，期望 2", got)
+	}
+	// 6个请求、每次2个并发、每个50ms
+	within(t, "6个请求 每主机2个连接", elapsed, 150*time.Millisecond)
+}
+
+func TestDownloadCancel(t *testing.T) {
+	srv, _ := newServer(t, 10, 0)
+	d := New(Config{RequestsPerSecond: 1}, HTTPFetcher(srv.Client()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results := d.DownloadAll(ctx, urls(srv, 3))
+
+	failed := 0
+	for _, r := range results {
+		if errors.Is(r.Err, context.DeadlineExceeded) {
+			failed++
+		}
+	}
+	// 第一个请求用掉初始令牌，另外两个等不到令牌
+	if failed != 2 {
+		t.Errorf("%d 个请求因超时失败，期望 2", failed)
+	}
+}
+
+func TestHTTPFetcherStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := New(Config{}, HTTPFetcher(srv.Client())).Download(context.Background(), 1, srv.URL)
+	if err == nil {
+		t.Fatal("404 应当返回错误")
+	}
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	tb := NewTokenBucket(100, 1)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tb.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	within(t, "10个令牌 @100/s", time.Since(start), 90*time.Millisecond)
+}
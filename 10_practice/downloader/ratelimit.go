@@ -0,0 +1,115 @@
+package downloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶限速器，可被多个goroutine共享
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 桶容量
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket 创建令牌桶，rate为每秒令牌数，burst为桶容量
+// rate <= 0 时返回nil，nil限速器表示不限速
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait 获取一个令牌
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	return tb.WaitN(ctx, 1)
+}
+
+// WaitN 获取n个令牌，令牌不足时阻塞直到补足或ctx取消
+// n可以大于桶容量，此时令牌会被"预支"，后续调用者需等待更久
+func (tb *TokenBucket) WaitN(ctx context.Context, n int) error {
+	if tb == nil || n <= 0 {
+		return nil
+	}
+
+	tb.mu.Lock()
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+	tb.tokens -= float64(n)
+
+	var wait time.Duration
+	if tb.tokens < 0 {
+		wait = time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+	}
+	tb.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 归还未使用的令牌
+		tb.mu.Lock()
+		tb.tokens += float64(n)
+		tb.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// hostLimiter 限制每个主机的并发连接数
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// acquire 占用host的一个连接名额，返回释放函数
+func (hl *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	if hl.limit <= 0 {
+		return func() {}, nil
+	}
+
+	hl.mu.Lock()
+	sem, ok := hl.slots[host]
+	if !ok {
+		sem = make(chan struct{}, hl.limit)
+		hl.slots[host] = sem
+	}
+	hl.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,90 @@
+// Package exercise 定义练习接口和注册表，菜单根据注册表动态生成
+package exercise
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"io"
+
+	"go-learn/10_practice/i18n"
+)
+
+// Exercise 一个可以从菜单中运行的练习
+type Exercise interface {
+	Name() string        // 唯一标识，如 "calculator"
+	Description() string // 菜单中显示的说明
+	Run(ctx context.Context, in io.Reader, out io.Writer) error
+}
+
+// Flagger 需要额外命令行参数的练习可以实现此接口，
+// 在解析命令行之前注册自己的参数（如计算器的 -expr）
+type Flagger interface {
+	RegisterFlags(fs *flag.FlagSet)
+}
+
+// Registry 按注册顺序保存练习
+type Registry struct {
+	exercises []Exercise
+	byName    map[string]Exercise
+}
+
+// NewRegistry 创建空注册表
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]Exercise)}
+}
+
+// Register 注册一个练习，名称重复时返回错误
+func (r *Registry) Register(e Exercise) error {
+	if _, ok := r.byName[e.Name()]; ok {
+		return errors.New(i18n.T("exercise.duplicate", e.Name()))
+	}
+	r.exercises = append(r.exercises, e)
+	r.byName[e.Name()] = e
+	return nil
+}
+
+// MustRegister 与 Register 相同，但出错时panic，适合在init中使用
+func (r *Registry) MustRegister(e Exercise) {
+	if err := r.Register(e); err != nil {
+		panic(err)
+	}
+}
+
+// All 按注册顺序返回所有练习
+func (r *Registry) All() []Exercise {
+	return append([]Exercise(nil), r.exercises...)
+}
+
+// Lookup 按名称查找练习
+func (r *Registry) Lookup(name string) (Exercise, bool) {
+	e, ok := r.byName[name]
+	return e, ok
+}
+
+// RegisterFlags 让注册表中所有实现了 Flagger 的练习注册命令行参数
+func (r *Registry) RegisterFlags(fs *flag.FlagSet) {
+	for _, e := range r.exercises {
+		if f, ok := e.(Flagger); ok {
+			f.RegisterFlags(fs)
+		}
+	}
+}
+
+// Default 默认注册表
+var Default = NewRegistry()
+
+// Register 向默认注册表注册练习
+func Register(e Exercise) {
+	Default.MustRegister(e)
+}
+
+// LineReader 返回按行读取in的*bufio.Reader
+// 如果in本身就是*bufio.Reader则直接返回，保证菜单和练习共享同一个缓冲区
+func LineReader(in io.Reader) *bufio.Reader {
+	if br, ok := in.(*bufio.Reader); ok {
+		return br
+	}
+	return bufio.NewReader(in)
+}
@@ -0,0 +1,129 @@
+package exercise
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fake 测试用的练习，把名称写到输出
+type fake struct{ name string }
+
+func (f fake) Name() string        { return f.name }
+func (f fake) Description() string { return "测试练习 " + f.name }
+func (f fake) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	_, err := io.WriteString(out, f.name)
+	return err
+}
+
+// withFlag 额外注册一个命令行参数的练习
+type withFlag struct {
+	fake
+	value *string
+}
+
+func (w *withFlag) RegisterFlags(fs *flag.FlagSet) {
+	w.value = fs.String(w.name+"-opt", "默认", "测试参数")
+}
+
+func names(es []Exercise) []string {
+	var ns []string
+	for _, e := range es {
+		ns = append(ns, e.Name())
+	}
+	return ns
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(fake{"calc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(fake{"calc"}); err == nil || !strings.Contains(err.Error(), "calc") {
+		t.Errorf("重复注册返回 %v，期望提到名称的错误", err)
+	}
+	if len(r.All()) != 1 {
+		t.Errorf("重复注册后有 %d 个练习，期望 1", len(r.All()))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister 重复注册应panic")
+		}
+	}()
+	r.MustRegister(fake{"calc"})
+}
+
+func TestLookup(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(fake{"guess"})
+
+	e, ok := r.Lookup("guess")
+	if !ok || e.Name() != "guess" {
+		t.Errorf("Lookup(guess) = %v, %v", e, ok)
+	}
+	if e, ok := r.Lookup("unknown"); ok || e != nil {
+		t.Errorf("Lookup(unknown) = %v, %v，期望 nil, false", e, ok)
+	}
+}
+
+func TestAllOrder(t *testing.T) {
+	r := NewRegistry()
+	if got := r.All(); len(got) != 0 {
+		t.Errorf("空注册表 All = %v", names(got))
+	}
+	for _, n := range []string{"students", "downloader", "calculator", "guess"} {
+		r.MustRegister(fake{n})
+	}
+
+	want := []string{"students", "downloader", "calculator", "guess"}
+	all := r.All()
+	if got := names(all); !reflect.DeepEqual(got, want) {
+		t.Errorf("All = %v，期望按注册顺序 %v", got, want)
+	}
+
+	// 返回的是副本，修改它不影响注册表
+	all[0] = fake{"changed"}
+	if got := names(r.All()); !reflect.DeepEqual(got, want) {
+		t.Errorf("修改 All 的结果后注册表变成了 %v", got)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	r := NewRegistry()
+	calc := &withFlag{fake: fake{"calc"}}
+	r.MustRegister(fake{"plain"})
+	r.MustRegister(calc)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	r.RegisterFlags(fs)
+	if fs.Lookup("calc-opt") == nil {
+		t.Fatal("实现了 Flagger 的练习没有注册参数")
+	}
+	if err := fs.Parse([]string{"-calc-opt=2+3"}); err != nil {
+		t.Fatal(err)
+	}
+	if *calc.value != "2+3" {
+		t.Errorf("参数值 = %q，期望 \"2+3\"", *calc.value)
+	}
+
+	n := 0
+	fs.VisitAll(func(*flag.Flag) { n++ })
+	if n != 1 {
+		t.Errorf("注册了 %d 个参数，期望只有 calc-opt", n)
+	}
+}
+
+func TestLineReader(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader("a\n"))
+	if LineReader(br) != br {
+		t.Error("已经是 *bufio.Reader 时应直接返回")
+	}
+	if line, _ := LineReader(strings.NewReader("b\nc\n")).ReadString('\n'); line != "b\n" {
+		t.Errorf("ReadString = %q", line)
+	}
+}
@@ -0,0 +1,118 @@
+// Package fileproc 提供词频统计等文本处理功能
+package fileproc
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxChunk 单次交给分词器的最大文本长度（字节）
+// 超长的无分隔文本（如未分词的中文）会被强制切成多段，段与段交界处最多丢失一个二元组，
+// 换来的是内存占用有上限，任意大的文件都能处理
+const maxChunk = 64 * 1024
+
+// scanChunks 是一个 bufio.SplitFunc：以空白和标点为界切出连续的文字，
+// 连续文字超过 maxChunk 时强制切分，因此不会像 bufio.ScanWords 那样返回 bufio.ErrTooLong
+func scanChunks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		if !atEOF && !utf8.FullRune(data[start:]) {
+			return start, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[start:])
+		if isWordRune(r) || isCJK(r) {
+			break
+		}
+		start += size
+	}
+
+	for i := start; i < len(data); {
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			return start, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if !isWordRune(r) && !isCJK(r) {
+			return i + size, data[start:i], nil
+		}
+		if i+size-start > maxChunk {
+			return i, data[start:i], nil
+		}
+		i += size
+	}
+
+	if atEOF && start < len(data) {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+// newScanner 返回按 scanChunks 切分的 Scanner
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), 2*maxChunk)
+	scanner.Split(scanChunks)
+	return scanner
+}
+
+// FileProcessor 文件处理器
+type FileProcessor struct {
+	// Tokenizer 分词器，为nil时使用默认的 UnicodeTokenizer
+	Tokenizer Tokenizer
+	// Logger 诊断日志，为nil时使用 slog.Default()
+	Logger *slog.Logger
+}
+
+func (fp FileProcessor) logger() *slog.Logger {
+	if fp.Logger == nil {
+		return slog.Default().With("component", "fileproc")
+	}
+	return fp.Logger
+}
+
+func (fp FileProcessor) tokenizer() Tokenizer {
+	if fp.Tokenizer == nil {
+		return UnicodeTokenizer{}
+	}
+	return fp.Tokenizer
+}
+
+// CountWords 统计文本中每个单词出现的次数
+func (fp FileProcessor) CountWords(content string) map[string]int {
+	wordCount, _ := fp.CountWordsReader(strings.NewReader(content))
+	return wordCount
+}
+
+// CountWordsReader 以流的方式从r中读取并统计词频，不会把整个文件读入内存
+func (fp FileProcessor) CountWordsReader(r io.Reader) (map[string]int, error) {
+	wordCount := make(map[string]int)
+
+	scanner := newScanner(r)
+
+	tokenizer := fp.tokenizer()
+	for scanner.Scan() {
+		for _, word := range tokenizer.Tokenize(scanner.Text()) {
+			wordCount[word]++
+		}
+	}
+
+	return wordCount, scanner.Err()
+}
+
+// FindMostFrequentWord 返回出现次数最多的单词及其次数，次数相同时取字典序最小的词
+func (fp FileProcessor) FindMostFrequentWord(wordCount map[string]int) (string, int) {
+	top := fp.TopK(wordCount, 1)
+	if len(top) == 0 {
+		return "", 0
+	}
+	return top[0].Word, top[0].Count
+}
+
+// Merge 把src中的词频累加到dst中
+func Merge(dst, src map[string]int) {
+	for word, count := range src {
+		dst[word] += count
+	}
+}
@@ -0,0 +1,67 @@
+package fileproc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountWordsReader(t *testing.T) {
+	fp := FileProcessor{}
+	got, err := fp.CountWordsReader(strings.NewReader("Go is fun, go is fast.\nGo!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"go": 3, "is": 2, "fun": 1, "fast": 1}
+	if len(got) != len(want) {
+		t.Fatalf("CountWordsReader = %v，期望 %v", got, want)
+	}
+	for w, n := range want {
+		if got[w] != n {
+			t.Errorf("%q 出现 %d 次，期望 %d", w, got[w], n)
+		}
+	}
+}
+
+// 超过1MiB的无空白文本以前会让 bufio.ScanWords 返回 bufio.ErrTooLong
+func TestLongRunWithoutWhitespace(t *testing.T) {
+	const n = 600_000 // 每个汉字3字节，约1.8MB
+	text := strings.Repeat("字", n)
+	fp := FileProcessor{Tokenizer: UnicodeTokenizer{HanMode: HanUnigram}}
+
+	counts, err := fp.CountWordsReader(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("CountWordsReader: %v", err)
+	}
+	if counts["字"] != n {
+		t.Errorf("统计到 %d 个字，期望 %d", counts["字"], n)
+	}
+
+	grams, err := fp.CountNGramsReader(strings.NewReader(text), 2)
+	if err != nil {
+		t.Fatalf("CountNGramsReader: %v", err)
+	}
+	if grams["字 字"] != n-1 {
+		t.Errorf("统计到 %d 个二元组，期望 %d", grams["字 字"], n-1)
+	}
+
+	st, err := fp.Stats(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if st.Words != n {
+		t.Errorf("Stats.Words = %d，期望 %d", st.Words, n)
+	}
+}
+
+func TestScanChunksSplitsMultibyteSafely(t *testing.T) {
+	// 让强制切分点落在多字节字符附近，确保不会切出半个字符
+	text := strings.Repeat("a", maxChunk-1) + "语言"
+	fp := FileProcessor{Tokenizer: UnicodeTokenizer{HanMode: HanUnigram}}
+	counts, err := fp.CountWordsReader(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["语"] != 1 || counts["言"] != 1 {
+		t.Errorf("汉字统计错误: 语=%d 言=%d", counts["语"], counts["言"])
+	}
+}
@@ -0,0 +1,149 @@
+package fileproc
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Stats 文本统计信息
+type Stats struct {
+	Lines     int `json:"lines"`
+	Words     int `json:"words"`
+	Chars     int `json:"chars"` // Unicode字符数（rune数）
+	Bytes     int `json:"bytes"`
+	Sentences int `json:"sentences"`
+	Syllables int `json:"syllables"` // 估算值：英文按元音组计，汉字每字一个音节
+	Unique    int `json:"unique_words"`
+
+	AvgSentenceLength float64 `json:"avg_sentence_length"` // 平均每句词数
+	LexicalDiversity  float64 `json:"lexical_diversity"`   // 不同词数 / 总词数
+	// FleschReadingEase Flesch易读性分数，越高越易读
+	// 公式基于英文的音节和词长，对以汉字为主的文本没有意义，此时为nil
+	FleschReadingEase *float64 `json:"flesch_reading_ease,omitempty"`
+}
+
+// isSentenceEnd 判断是否为句末标点（中英文）
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '…':
+		return true
+	}
+	return false
+}
+
+// countSyllables 粗略估算一个非汉字词的音节数，按元音组计
+// 汉字的音节数按字在 Stats 中单独统计，因为二元组会让同一个字出现在两个词里
+func countSyllables(word string) int {
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := false
+		switch unicode.ToLower(r) {
+		case 'a', 'e', 'i', 'o', 'u', 'y':
+			vowel = true
+		}
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+// Stats 以流的方式计算r的统计信息
+// 行数、字符数和字节数的含义与wc一致，词按 FileProcessor 的分词器统计
+func (fp FileProcessor) Stats(r io.Reader) (Stats, error) {
+	var st Stats
+	words := make(map[string]int)
+	tokenizer := fp.tokenizer()
+
+	br := bufio.NewReader(r)
+	var pending []byte // 尚未分词的连续文字
+	inSentence := false
+	han, other := 0, 0 // 汉字和其他文字的字符数，用于判断是否适用Flesch公式
+
+	flush := func() {
+		for _, r := range string(pending) {
+			if isCJK(r) {
+				st.Syllables++
+			}
+		}
+		for _, w := range tokenizer.Tokenize(string(pending)) {
+			words[w]++
+			st.Words++
+			if !strings.ContainsFunc(w, isCJK) {
+				st.Syllables += countSyllables(w)
+			}
+		}
+		pending = pending[:0]
+	}
+
+	for {
+		ch, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return st, err
+		}
+
+		st.Chars++
+		st.Bytes += size
+		if ch == utf8.RuneError && size == 1 {
+			continue
+		}
+
+		switch {
+		case ch == '\n':
+			st.Lines++
+			flush()
+		case isWordRune(ch) || isCJK(ch):
+			if isCJK(ch) {
+				han++
+			} else {
+				other++
+			}
+			if len(pending) >= maxChunk {
+				flush()
+			}
+			pending = utf8.AppendRune(pending, ch)
+		default:
+			// 空白和标点都是分词边界，及时分词可避免缓存过长的文本
+			flush()
+		}
+
+		if isSentenceEnd(ch) {
+			if inSentence {
+				st.Sentences++
+			}
+			inSentence = false
+		} else if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			inSentence = true
+		}
+	}
+	flush()
+	if inSentence {
+		st.Sentences++
+	}
+
+	st.Unique = len(words)
+	if st.Words > 0 {
+		st.LexicalDiversity = float64(st.Unique) / float64(st.Words)
+	}
+	if st.Sentences > 0 && st.Words > 0 {
+		st.AvgSentenceLength = float64(st.Words) / float64(st.Sentences)
+	}
+	if st.Sentences > 0 && st.Words > 0 && han <= other {
+		flesch := 206.835 - 1.015*st.AvgSentenceLength -
+			84.6*float64(st.Syllables)/float64(st.Words)
+		st.FleschReadingEase = &flesch
+	}
+
+	return st, nil
+}
@@ -0,0 +1,48 @@
+package fileproc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		words     int
+		sentences int
+		syllables int
+		flesch    bool // 是否计算Flesch分数
+	}{
+		{"英文", "The cat sat. It was happy!\n", 6, 2, 7, true},
+		// 二元组会让中间的字出现两次，音节仍应按字计
+		{"中文二元组", "语言简洁高效。", 5, 1, 6, false},
+		{"中英混排", "Go语言很好。", 4, 1, 5, false},
+		{"以英文为主", "Go is simple. 简单", 4, 2, 6, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := FileProcessor{}.Stats(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.Words != tt.words || st.Sentences != tt.sentences || st.Syllables != tt.syllables {
+				t.Errorf("词/句/音节 = %d/%d/%d，期望 %d/%d/%d",
+					st.Words, st.Sentences, st.Syllables, tt.words, tt.sentences, tt.syllables)
+			}
+			if (st.FleschReadingEase != nil) != tt.flesch {
+				t.Errorf("FleschReadingEase = %v，期望计算=%v", st.FleschReadingEase, tt.flesch)
+			}
+		})
+	}
+}
+
+func TestStatsCounts(t *testing.T) {
+	st, err := FileProcessor{}.Stats(strings.NewReader("你好\nhi\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Lines != 2 || st.Chars != 6 || st.Bytes != 10 {
+		t.Errorf("行/字符/字节 = %d/%d/%d，期望 2/6/10", st.Lines, st.Chars, st.Bytes)
+	}
+}
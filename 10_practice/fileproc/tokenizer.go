@@ -0,0 +1,119 @@
+package fileproc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer 把一段文本切分为词
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// HanMode 汉字（以及日文假名）的切分方式
+type HanMode int
+
+const (
+	HanBigram  HanMode = iota // 按相邻两个字切分，如"语言简洁" -> 语言 言简 简洁
+	HanUnigram                // 按单字切分
+)
+
+// Stopwords 停用词集合，统计时会被忽略
+type Stopwords map[string]struct{}
+
+// NewStopwords 用给定的词创建停用词集合，词会被转为小写
+func NewStopwords(words ...string) Stopwords {
+	s := make(Stopwords, len(words))
+	for _, w := range words {
+		s[strings.ToLower(w)] = struct{}{}
+	}
+	return s
+}
+
+// Contains 判断word是否为停用词
+func (s Stopwords) Contains(word string) bool {
+	_, ok := s[word]
+	return ok
+}
+
+// 常用停用词表
+var (
+	EnglishStopwords = NewStopwords(
+		"a", "an", "and", "are", "as", "at", "be", "by", "for", "from",
+		"in", "is", "it", "of", "on", "or", "that", "the", "to", "was", "with",
+	)
+	ChineseStopwords = NewStopwords(
+		"的", "了", "是", "在", "和", "也", "就", "都", "而", "及", "与", "着", "或",
+	)
+)
+
+// UnicodeTokenizer 基于Unicode字符类别的默认分词器：
+//   - 拉丁字母、数字等连续字符组成一个词，统一转为小写
+//   - 汉字与日文假名按 HanMode 切分为单字或二元组；单字停用词（如"的"）在切分前
+//     就被当作分隔符去掉，因此在二元组模式下同样生效
+//   - 所有空白、Unicode标点和符号都作为分隔符
+type UnicodeTokenizer struct {
+	HanMode   HanMode
+	Stopwords Stopwords
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Tokenize 实现 Tokenizer 接口
+func (t UnicodeTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	var han []rune
+
+	emit := func(tok string) {
+		if tok != "" && !t.Stopwords.Contains(tok) {
+			tokens = append(tokens, tok)
+		}
+	}
+	flushWord := func() {
+		emit(strings.ToLower(word.String()))
+		word.Reset()
+	}
+	flushHan := func() {
+		switch {
+		case len(han) == 0:
+		case t.HanMode == HanUnigram || len(han) == 1:
+			for _, r := range han {
+				emit(string(r))
+			}
+		default:
+			for i := 0; i+1 < len(han); i++ {
+				emit(string(han[i : i+2]))
+			}
+		}
+		han = han[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			if t.Stopwords.Contains(string(r)) {
+				flushHan()
+				continue
+			}
+			han = append(han, r)
+		case isWordRune(r):
+			flushHan()
+			word.WriteRune(r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+
+	return tokens
+}
@@ -0,0 +1,37 @@
+package fileproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		tok  UnicodeTokenizer
+		text string
+		want []string
+	}{
+		{"英文转小写", UnicodeTokenizer{}, "Hello, World!", []string{"hello", "world"}},
+		{"二元组", UnicodeTokenizer{}, "语言简洁", []string{"语言", "言简", "简洁"}},
+		{"单字", UnicodeTokenizer{HanMode: HanUnigram}, "语言", []string{"语", "言"}},
+		{"中英混排", UnicodeTokenizer{}, "Go语言", []string{"go", "语言"}},
+		{"英文停用词", UnicodeTokenizer{Stopwords: EnglishStopwords}, "the cat and the hat",
+			[]string{"cat", "hat"}},
+		{"二元组模式下的中文停用词", UnicodeTokenizer{Stopwords: ChineseStopwords}, "我的书和你的笔",
+			[]string{"我", "书", "你", "笔"}},
+		{"停用词切断二元组", UnicodeTokenizer{Stopwords: ChineseStopwords}, "简单的语言",
+			[]string{"简单", "语言"}},
+		{"单字模式下的中文停用词", UnicodeTokenizer{HanMode: HanUnigram, Stopwords: ChineseStopwords},
+			"书是我的", []string{"书", "我"}},
+		{"二元组停用词", UnicodeTokenizer{Stopwords: NewStopwords("我们")}, "我们的",
+			[]string{"们的"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tok.Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q，期望 %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package fileproc
+
+import (
+	"container/heap"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WordFreq 一个词及其出现次数
+type WordFreq struct {
+	Word  string
+	Count int
+}
+
+// less 定义排名顺序：次数多的在前，次数相同时按字典序
+func (a WordFreq) less(b WordFreq) bool {
+	if a.Count != b.Count {
+		return a.Count > b.Count
+	}
+	return a.Word < b.Word
+}
+
+// freqHeap 以"排名最靠后"为堆顶的堆，用于保留前k个
+type freqHeap []WordFreq
+
+func (h freqHeap) Len() int           { return len(h) }
+func (h freqHeap) Less(i, j int) bool { return h[j].less(h[i]) }
+func (h freqHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *freqHeap) Push(x any)        { *h = append(*h, x.(WordFreq)) }
+func (h *freqHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// TopK 返回出现次数最多的k个词，次数相同时按字典序排列，结果确定
+// 使用大小为k的堆，时间复杂度 O(n log k)，适合词汇量很大的情况
+func (fp FileProcessor) TopK(wordCount map[string]int, k int) []WordFreq {
+	if k <= 0 {
+		return nil
+	}
+
+	h := make(freqHeap, 0, k)
+	for word, count := range wordCount {
+		wf := WordFreq{Word: word, Count: count}
+		if h.Len() < k {
+			heap.Push(&h, wf)
+		} else if wf.less(h[0]) {
+			h[0] = wf
+			heap.Fix(&h, 0)
+		}
+	}
+
+	result := []WordFreq(h)
+	sort.Slice(result, func(i, j int) bool { return result[i].less(result[j]) })
+	return result
+}
+
+// CountNGrams 统计相邻n个词组成的词组出现次数，词之间以空格连接
+// n为2时即二元组(bigram)，n为3时即三元组(trigram)
+func (fp FileProcessor) CountNGrams(content string, n int) map[string]int {
+	counts, _ := fp.CountNGramsReader(strings.NewReader(content), n)
+	return counts
+}
+
+// CountNGramsReader 以流的方式统计n元组，只保留最近n个词的滑动窗口
+func (fp FileProcessor) CountNGramsReader(r io.Reader, n int) (map[string]int, error) {
+	counts := make(map[string]int)
+	if n <= 0 {
+		return counts, nil
+	}
+
+	scanner := newScanner(r)
+
+	tokenizer := fp.tokenizer()
+	window := make([]string, 0, n)
+	for scanner.Scan() {
+		for _, word := range tokenizer.Tokenize(scanner.Text()) {
+			if len(window) == n {
+				copy(window, window[1:])
+				window = window[:n-1]
+			}
+			window = append(window, word)
+			if len(window) == n {
+				counts[strings.Join(window, " ")]++
+			}
+		}
+	}
+
+	return counts, scanner.Err()
+}
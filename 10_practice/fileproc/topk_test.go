@@ -0,0 +1,66 @@
+package fileproc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	counts := map[string]int{"go": 3, "b": 2, "a": 2, "c": 2, "rust": 1}
+	tests := []struct {
+		name string
+		k    int
+		want []WordFreq
+	}{
+		{"次数相同按字典序", 3, []WordFreq{{"go", 3}, {"a", 2}, {"b", 2}}},
+		{"截断在并列的词中间", 2, []WordFreq{{"go", 3}, {"a", 2}}},
+		{"k大于词汇量", 10, []WordFreq{{"go", 3}, {"a", 2}, {"b", 2}, {"c", 2}, {"rust", 1}}},
+		{"k为0", 0, nil},
+		{"k为负数", -1, nil},
+	}
+	fp := FileProcessor{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// map遍历顺序随机，多跑几次确认结果确定
+			for i := 0; i < 20; i++ {
+				if got := fp.TopK(counts, tt.k); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("TopK(%d) = %v，期望 %v", tt.k, got, tt.want)
+				}
+			}
+		})
+	}
+
+	if got := fp.TopK(nil, 3); len(got) != 0 {
+		t.Errorf("空词表 TopK = %v，期望为空", got)
+	}
+}
+
+func TestCountNGrams(t *testing.T) {
+	const text = "The cat sat.\nThe cat ran"
+	tests := []struct {
+		name string
+		n    int
+		want map[string]int
+	}{
+		{"一元组", 1, map[string]int{"the": 2, "cat": 2, "sat": 1, "ran": 1}},
+		{"二元组跨行", 2, map[string]int{"the cat": 2, "cat sat": 1, "sat the": 1, "cat ran": 1}},
+		{"三元组", 3, map[string]int{"the cat sat": 1, "cat sat the": 1, "sat the cat": 1, "the cat ran": 1}},
+		{"n大于词数", 7, map[string]int{}},
+		{"n为0", 0, map[string]int{}},
+	}
+	fp := FileProcessor{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fp.CountNGrams(text, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountNGrams(%d) = %v，期望 %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	// 中文按分词器的结果组成词组
+	uni := FileProcessor{Tokenizer: UnicodeTokenizer{HanMode: HanUnigram}}
+	want := map[string]int{"你 好": 2, "好 你": 1}
+	if got := uni.CountNGrams("你好你好", 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("单字二元组 = %v，期望 %v", got, want)
+	}
+}
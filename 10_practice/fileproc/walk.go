@@ -0,0 +1,110 @@
+package fileproc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// fileResult 单个文件的统计结果
+type fileResult struct {
+	path   string
+	counts map[string]int
+	err    error
+}
+
+// openFile 打开要统计的文件，测试中替换它来模拟无法读取的文件
+var openFile = os.Open
+
+// countFile 统计单个文件的词频
+func (fp FileProcessor) countFile(path string) (map[string]int, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return fp.CountWordsReader(f)
+}
+
+// worker 从jobs中取文件路径，统计后把结果发送到results
+func (fp FileProcessor) worker(jobs <-chan string, results chan<- fileResult) {
+	for path := range jobs {
+		counts, err := fp.countFile(path)
+		results <- fileResult{path: path, counts: counts, err: err}
+	}
+}
+
+// CountDir 使用工作池并发统计root目录树下所有普通文件的词频并合并（跳过隐藏目录）
+// workers <= 0 时使用CPU核数。单个文件出错不会中断统计，所有错误合并后返回
+func (fp FileProcessor) CountDir(root string, workers int) (map[string]int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan string, workers)
+	results := make(chan fileResult, workers)
+
+	// 启动工作者
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fp.worker(jobs, results)
+		}()
+	}
+
+	// 遍历目录发送工作
+	var walkErr error
+	go func() {
+		walkErr = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// 跳过 .git 等隐藏目录
+			if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if d.Type().IsRegular() {
+				jobs <- path
+			}
+			return nil
+		})
+		close(jobs)
+	}()
+
+	// 所有工作者退出后关闭results
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 合并结果
+	total := make(map[string]int)
+	var errs []error
+	log := fp.logger()
+	files := 0
+	for r := range results {
+		if r.err != nil {
+			log.Warn("统计文件失败", "path", r.path, "err", r.err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.path, r.err))
+			continue
+		}
+		log.Debug("统计文件完成", "path", r.path, "distinct", len(r.counts))
+		files++
+		Merge(total, r.counts)
+	}
+
+	if walkErr != nil {
+		log.Warn("遍历目录失败", "root", root, "err", walkErr)
+		errs = append(errs, walkErr)
+	}
+	log.Info("目录统计完成", "root", root, "files", files, "distinct", len(total), "errors", len(errs))
+	return total, errors.Join(errs...)
+}
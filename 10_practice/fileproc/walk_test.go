@@ -0,0 +1,99 @@
+package fileproc
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTree 在临时目录中按 相对路径->内容 创建文件，返回根目录
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestCountDir(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"a.txt":            "go is fun",
+		"b.txt":            "Go go GO",
+		"sub/c.md":         "fun fun",
+		"sub/deep/d.txt":   "is",
+		".git/config":      "go hidden",
+		"sub/.cache/e.txt": "hidden hidden",
+		".hidden.txt":      "dot", // 只跳过隐藏目录，隐藏文件照常统计
+	})
+	want := map[string]int{"go": 4, "is": 2, "fun": 3, "dot": 1}
+
+	for _, workers := range []int{0, 1, 4} {
+		got, err := FileProcessor{}.CountDir(root, workers)
+		if err != nil {
+			t.Fatalf("workers=%d: %v", workers, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: CountDir = %v，期望 %v", workers, got, want)
+		}
+	}
+}
+
+func TestCountDirHiddenRoot(t *testing.T) {
+	// 根目录本身以点开头时不能被跳过
+	root := writeTree(t, map[string]string{".data/a.txt": "go"})
+	got, err := FileProcessor{}.CountDir(filepath.Join(root, ".data"), 2)
+	if err != nil || got["go"] != 1 {
+		t.Errorf("CountDir = %v, %v，期望统计隐藏的根目录", got, err)
+	}
+}
+
+// TestCountDirErrors 无法读取的文件不影响其他文件，所有错误合并后返回
+func TestCountDirErrors(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"ok.txt":       "go go",
+		"bad1.txt":     "lost",
+		"sub/bad2.txt": "lost",
+	})
+	defer func(old func(string) (*os.File, error)) { openFile = old }(openFile)
+	openFile = func(path string) (*os.File, error) {
+		if strings.HasPrefix(filepath.Base(path), "bad") {
+			return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrPermission}
+		}
+		return os.Open(path)
+	}
+
+	got, err := FileProcessor{}.CountDir(root, 2)
+	if !reflect.DeepEqual(got, map[string]int{"go": 2}) {
+		t.Errorf("CountDir = %v，期望只统计可读的文件", got)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("CountDir 返回 %v，期望包含 fs.ErrPermission", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("错误 %v 应合并了2个文件的错误", err)
+	}
+	for _, name := range []string{"bad1.txt", "bad2.txt"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("错误 %q 中没有提到 %s", err, name)
+		}
+	}
+}
+
+func TestCountDirMissingRoot(t *testing.T) {
+	_, err := FileProcessor{}.CountDir(filepath.Join(t.TempDir(), "missing"), 2)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CountDir 返回 %v，期望 fs.ErrNotExist", err)
+	}
+}
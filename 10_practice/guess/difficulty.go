@@ -0,0 +1,57 @@
+package guess
+
+import (
+	"math/rand"
+	"time"
+
+	"go-learn/10_practice/i18n"
+)
+
+// Difficulty 难度：决定数字范围、可猜次数和得分倍率
+type Difficulty struct {
+	Name        string // 英文标识，用于排行榜、命令行和查找显示名称
+	Min, Max    int
+	MaxAttempts int
+	Multiplier  float64
+}
+
+// Difficulties 可选的难度，按从易到难排列
+var Difficulties = []Difficulty{
+	{Name: "easy", Min: 1, Max: 50, MaxAttempts: 10, Multiplier: 1},
+	{Name: "normal", Min: 1, Max: 100, MaxAttempts: 7, Multiplier: 2},
+	{Name: "hard", Min: 1, Max: 1000, MaxAttempts: 10, Multiplier: 3},
+}
+
+// DifficultyByName 按名称查找难度
+func DifficultyByName(name string) (Difficulty, bool) {
+	for _, d := range Difficulties {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Difficulty{}, false
+}
+
+// Label 返回当前语言下的难度名称
+func (d Difficulty) Label() string {
+	return i18n.T("guess.difficulty." + d.Name)
+}
+
+// NewGame 按该难度创建一局游戏
+func (d Difficulty) NewGame(src rand.Source) (*Game, error) {
+	return NewGame(src, d.Min, d.Max, d.MaxAttempts)
+}
+
+// Score 计算得分：剩余次数越多越高，每用时一秒扣1分，未猜中得0分
+func (d Difficulty) Score(g *Game, elapsed time.Duration) int {
+	if g.State() != Won {
+		return 0
+	}
+
+	base := 1000 * float64(g.MaxAttempts-g.Attempts()+1) / float64(g.MaxAttempts)
+	score := int(base*d.Multiplier) - int(elapsed.Seconds())
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
@@ -0,0 +1,54 @@
+package guess
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScore(t *testing.T) {
+	normal, _ := DifficultyByName("normal") // 7次，倍率2
+	hard, _ := DifficultyByName("hard")     // 10次，倍率3
+
+	// play 在目标为50的游戏中依次猜guesses
+	play := func(d Difficulty, guesses ...int) *Game {
+		g := &Game{Min: d.Min, Max: d.Max, MaxAttempts: d.MaxAttempts, target: 50}
+		for _, n := range guesses {
+			g.Guess(n)
+		}
+		return g
+	}
+
+	tests := []struct {
+		name    string
+		d       Difficulty
+		game    *Game
+		elapsed time.Duration
+		want    int
+	}{
+		{"一次猜中", normal, play(normal, 50), 0, 2000},
+		{"用时扣分", normal, play(normal, 50), 30 * time.Second, 1970},
+		{"不足一秒不扣分", normal, play(normal, 50), 900 * time.Millisecond, 2000},
+		{"用了3次", normal, play(normal, 10, 90, 50), 0, 1428}, // 1000*5/7*2
+		{"倍率更高", hard, play(hard, 10, 90, 50), 0, 2400},     // 1000*8/10*3
+		{"最后一次猜中", normal, play(normal, 1, 2, 3, 4, 5, 6, 50), 0, 285},
+		{"不会扣成负数", normal, play(normal, 1, 2, 3, 4, 5, 6, 50), time.Hour, 0},
+		{"没猜中", normal, play(normal, 1, 2, 3, 4, 5, 6, 7), 0, 0},
+		{"还在进行", normal, play(normal, 1), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Score(tt.game, tt.elapsed); got != tt.want {
+			t.Errorf("%s: Score = %d，期望 %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDifficultyByName(t *testing.T) {
+	for _, d := range Difficulties {
+		if got, ok := DifficultyByName(d.Name); !ok || got != d {
+			t.Errorf("DifficultyByName(%q) = %v, %v", d.Name, got, ok)
+		}
+	}
+	if _, ok := DifficultyByName("impossible"); ok {
+		t.Error("未知难度应返回false")
+	}
+}
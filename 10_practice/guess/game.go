@@ -0,0 +1,117 @@
+// Package guess 实现猜数字游戏的引擎，游戏逻辑与输入输出分离，便于测试
+package guess
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"go-learn/10_practice/i18n"
+)
+
+// State 游戏状态
+type State int
+
+const (
+	Playing State = iota // 进行中
+	Won                  // 猜中了
+	Lost                 // 次数用完
+)
+
+func (s State) String() string {
+	switch s {
+	case Playing:
+		return i18n.T("guess.state.playing")
+	case Won:
+		return i18n.T("guess.state.won")
+	case Lost:
+		return i18n.T("guess.state.lost")
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Feedback 一次猜测的结果
+type Feedback int
+
+const (
+	Invalid Feedback = iota // 猜测无效（超出范围或游戏已结束），总是伴随一个错误返回
+	TooLow                  // 太小了
+	Correct                 // 猜对了
+	TooHigh                 // 太大了
+)
+
+// ErrGameOver 游戏结束后继续猜测时返回
+var ErrGameOver = i18n.Error("guess.game_over")
+
+// Game 猜数字游戏引擎，状态机: Playing -> Won / Lost
+type Game struct {
+	Min, Max    int // 目标数字范围（闭区间）
+	MaxAttempts int
+
+	target   int
+	attempts int
+	state    State
+}
+
+// NewGame 创建一局游戏，目标数字由src在[min, max]范围内随机生成
+// 传入固定种子的rand.Source即可得到可复现的游戏；max<min或maxAttempts<1时返回错误
+func NewGame(src rand.Source, min, max, maxAttempts int) (*Game, error) {
+	if max < min {
+		return nil, errors.New(i18n.T("guess.bad_range", min, max))
+	}
+	if maxAttempts < 1 {
+		return nil, errors.New(i18n.T("guess.bad_attempts", maxAttempts))
+	}
+	r := rand.New(src)
+	return &Game{
+		Min:         min,
+		Max:         max,
+		MaxAttempts: maxAttempts,
+		target:      min + r.Intn(max-min+1),
+	}, nil
+}
+
+// Guess 提交一次猜测
+func (g *Game) Guess(n int) (Feedback, error) {
+	if g.state != Playing {
+		return Invalid, ErrGameOver
+	}
+	if n < g.Min || n > g.Max {
+		return Invalid, errors.New(i18n.T("guess.out_of_range", g.Min, g.Max))
+	}
+
+	g.attempts++
+
+	var fb Feedback
+	switch {
+	case n == g.target:
+		fb = Correct
+		g.state = Won
+	case n < g.target:
+		fb = TooLow
+	default:
+		fb = TooHigh
+	}
+
+	if g.state == Playing && g.attempts >= g.MaxAttempts {
+		g.state = Lost
+	}
+	return fb, nil
+}
+
+// State 返回当前游戏状态
+func (g *Game) State() State { return g.state }
+
+// Attempts 返回已经猜测的次数
+func (g *Game) Attempts() int { return g.attempts }
+
+// Remaining 返回剩余的猜测次数
+func (g *Game) Remaining() int { return g.MaxAttempts - g.attempts }
+
+// Target 返回目标数字，游戏进行中时返回0，防止"偷看"
+func (g *Game) Target() int {
+	if g.state == Playing {
+		return 0
+	}
+	return g.target
+}
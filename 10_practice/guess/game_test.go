@@ -0,0 +1,154 @@
+package guess
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"go-learn/10_practice/i18n"
+)
+
+func TestNewGame(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g, err := NewGame(rand.NewSource(seed), 5, 8, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.target < 5 || g.target > 8 {
+			t.Fatalf("种子 %d 生成的目标 %d 不在 [5, 8] 内", seed, g.target)
+		}
+	}
+
+	a, _ := NewGame(rand.NewSource(42), 1, 1000, 10)
+	b, _ := NewGame(rand.NewSource(42), 1, 1000, 10)
+	if a.target != b.target {
+		t.Errorf("相同种子得到不同目标: %d 和 %d", a.target, b.target)
+	}
+}
+
+func TestNewGameInvalid(t *testing.T) {
+	tests := []struct {
+		name                  string
+		min, max, maxAttempts int
+	}{
+		{"范围颠倒", 10, 1, 5},
+		{"次数为0", 1, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGame(rand.NewSource(1), tt.min, tt.max, tt.maxAttempts)
+			if err == nil || g != nil {
+				t.Errorf("NewGame(%d, %d, %d) = %v, %v，期望返回错误", tt.min, tt.max, tt.maxAttempts, g, err)
+			}
+		})
+	}
+}
+
+func TestGuess(t *testing.T) {
+	g := &Game{Min: 1, Max: 100, MaxAttempts: 3, target: 42}
+
+	steps := []struct {
+		n     int
+		fb    Feedback
+		err   bool
+		state State
+	}{
+		{0, Invalid, true, Playing}, // 超出范围不计入次数
+		{50, TooHigh, false, Playing},
+		{10, TooLow, false, Playing},
+		{42, Correct, false, Won},
+		{42, Invalid, true, Won}, // 游戏结束后再猜
+	}
+	for _, s := range steps {
+		fb, err := g.Guess(s.n)
+		if fb != s.fb || (err != nil) != s.err || g.State() != s.state {
+			t.Fatalf("Guess(%d) = %v, %v，状态 %v；期望 %v, 错误=%v，状态 %v",
+				s.n, fb, err, g.State(), s.fb, s.err, s.state)
+		}
+	}
+	if g.Attempts() != 3 {
+		t.Errorf("Attempts() = %d，期望 3", g.Attempts())
+	}
+	if _, err := g.Guess(1); !errors.Is(err, ErrGameOver) {
+		t.Errorf("游戏结束后 Guess 返回 %v，期望 ErrGameOver", err)
+	}
+}
+
+func TestTargetHiddenWhilePlaying(t *testing.T) {
+	g := &Game{Min: 1, Max: 10, MaxAttempts: 1, target: 7}
+	if g.Target() != 0 {
+		t.Errorf("进行中 Target() = %d，期望 0", g.Target())
+	}
+	g.Guess(1)
+	if g.State() != Lost || g.Target() != 7 {
+		t.Errorf("用完次数后状态 %v、目标 %d，期望 失败、7", g.State(), g.Target())
+	}
+}
+
+// TestPlay 用脚本化的输入重放完整的对局
+func TestPlay(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		state    State
+		attempts int
+		want     []string // 输出中应出现的消息
+	}{
+		{
+			name:     "二分猜中",
+			input:    "50\n25\n37\n",
+			state:    Won,
+			attempts: 3,
+			want:     []string{i18n.T("guess.too_high"), i18n.T("guess.too_low"), i18n.T("guess.correct", 37)},
+		},
+		{
+			name:     "无效输入不消耗次数",
+			input:    "abc\n\n200\n37\n",
+			state:    Won,
+			attempts: 1,
+			want:     []string{i18n.T("guess.invalid_number"), i18n.T("guess.out_of_range", 1, 100)},
+		},
+		{
+			name:     "次数用完",
+			input:    "1\n2\n3\n4\n5\n",
+			state:    Lost,
+			attempts: 5,
+			want:     []string{i18n.T("guess.lost", 37)},
+		},
+		{
+			name:     "最后一行没有换行符",
+			input:    "37",
+			state:    Won,
+			attempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Game{Min: 1, Max: 100, MaxAttempts: 5, target: 37}
+			var out strings.Builder
+			if err := Play(g, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("Play: %v", err)
+			}
+			if g.State() != tt.state || g.Attempts() != tt.attempts {
+				t.Errorf("状态 %v、%d 次，期望 %v、%d 次", g.State(), g.Attempts(), tt.state, tt.attempts)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("输出中没有 %q:\n%s", w, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPlayInputEnds(t *testing.T) {
+	g := &Game{Min: 1, Max: 100, MaxAttempts: 5, target: 37}
+	var out strings.Builder
+	if err := Play(g, strings.NewReader("1\n"), &out); err == nil {
+		t.Error("输入提前结束时应返回错误")
+	}
+	if g.State() != Playing {
+		t.Errorf("状态 %v，期望 进行中", g.State())
+	}
+}
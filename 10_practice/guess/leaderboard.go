@@ -0,0 +1,122 @@
+package guess
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry 排行榜中的一条记录
+type Entry struct {
+	Name       string        `json:"name"`
+	Difficulty string        `json:"difficulty"`
+	Score      int           `json:"score"`
+	Attempts   int           `json:"attempts"`
+	Elapsed    time.Duration `json:"elapsed"`
+	PlayedAt   time.Time     `json:"played_at"`
+}
+
+// ranksBefore 排名顺序：得分高的在前，同分时先达成者在前
+func (e Entry) ranksBefore(o Entry) bool {
+	if e.Score != o.Score {
+		return e.Score > o.Score
+	}
+	return e.PlayedAt.Before(o.PlayedAt)
+}
+
+// MaxEntries 每个难度保留的记录数，超出的低分记录在 Add 时丢弃，文件不会无限增长
+const MaxEntries = 10
+
+// Leaderboard 保存在本地JSON文件中的排行榜
+type Leaderboard struct {
+	path    string
+	Entries []Entry `json:"entries"`
+}
+
+// DefaultLeaderboardPath 返回默认的排行榜文件路径
+func DefaultLeaderboardPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "guess_leaderboard.json"
+	}
+	return filepath.Join(dir, "go-learn", "guess_leaderboard.json")
+}
+
+// LoadLeaderboard 从path读取排行榜，文件不存在时返回空排行榜
+func LoadLeaderboard(path string) (*Leaderboard, error) {
+	lb := &Leaderboard{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lb, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, lb); err != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+// Add 添加一条记录，之后按难度和排名重新排列，每个难度只保留前 MaxEntries 条
+func (lb *Leaderboard) Add(e Entry) {
+	lb.Entries = append(lb.Entries, e)
+	sort.SliceStable(lb.Entries, func(i, j int) bool {
+		a, b := lb.Entries[i], lb.Entries[j]
+		if a.Difficulty != b.Difficulty {
+			return a.Difficulty < b.Difficulty
+		}
+		return a.ranksBefore(b)
+	})
+
+	kept := lb.Entries[:0]
+	perDifficulty := make(map[string]int)
+	for _, e := range lb.Entries {
+		if perDifficulty[e.Difficulty] < MaxEntries {
+			perDifficulty[e.Difficulty]++
+			kept = append(kept, e)
+		}
+	}
+	lb.Entries = kept
+}
+
+// Top 返回某个难度得分最高的n条记录，同分时先达成者在前
+func (lb *Leaderboard) Top(difficulty string, n int) []Entry {
+	var top []Entry
+	for _, e := range lb.Entries {
+		if e.Difficulty == difficulty {
+			top = append(top, e)
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool { return top[i].ranksBefore(top[j]) })
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Save 把排行榜写回文件，先写临时文件再重命名，避免写到一半时损坏
+func (lb *Leaderboard) Save() error {
+	if err := os.MkdirAll(filepath.Dir(lb.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(lb, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := lb.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, lb.path)
+}
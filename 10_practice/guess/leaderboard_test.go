@@ -0,0 +1,138 @@
+package guess
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func entry(name, difficulty string, score int, minutes int) Entry {
+	return Entry{
+		Name:       name,
+		Difficulty: difficulty,
+		Score:      score,
+		Attempts:   3,
+		Elapsed:    20 * time.Second,
+		PlayedAt:   day.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+func names(entries []Entry) []string {
+	var ns []string
+	for _, e := range entries {
+		ns = append(ns, e.Name)
+	}
+	return ns
+}
+
+func TestLeaderboardTop(t *testing.T) {
+	lb := &Leaderboard{}
+	lb.Add(entry("甲", "easy", 500, 0))
+	lb.Add(entry("乙", "easy", 900, 1))
+	lb.Add(entry("丙", "hard", 2000, 2))
+	lb.Add(entry("丁", "easy", 900, 3)) // 与乙同分但更晚
+	lb.Add(entry("戊", "easy", 700, 4))
+
+	tests := []struct {
+		difficulty string
+		n          int
+		want       []string
+	}{
+		{"easy", 3, []string{"乙", "丁", "戊"}},
+		{"easy", 10, []string{"乙", "丁", "戊", "甲"}},
+		{"hard", 5, []string{"丙"}},
+		{"normal", 5, nil},
+		{"easy", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := names(lb.Top(tt.difficulty, tt.n)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Top(%s, %d) = %v，期望 %v", tt.difficulty, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestLeaderboardTrim 每个难度只保留 MaxEntries 条，不影响其他难度
+func TestLeaderboardTrim(t *testing.T) {
+	lb := &Leaderboard{}
+	lb.Add(entry("hard", "hard", 1, 0))
+	for i := 0; i < MaxEntries+5; i++ {
+		lb.Add(entry(string(rune('a'+i)), "easy", i*10, i))
+	}
+
+	if len(lb.Entries) != MaxEntries+1 {
+		t.Fatalf("共 %d 条记录，期望 %d", len(lb.Entries), MaxEntries+1)
+	}
+	top := lb.Top("easy", MaxEntries+5)
+	if len(top) != MaxEntries || top[0].Score != (MaxEntries+4)*10 || top[len(top)-1].Score != 50 {
+		t.Errorf("easy 保留了 %v，期望最高的 %d 条", names(top), MaxEntries)
+	}
+	if got := lb.Top("hard", 5); len(got) != 1 {
+		t.Errorf("hard 的记录被裁掉了: %v", got)
+	}
+
+	// 分数太低的新记录不会留下
+	lb.Add(entry("low", "easy", 0, 100))
+	if got := lb.Top("easy", MaxEntries+5); !reflect.DeepEqual(got, top) {
+		t.Errorf("低分记录挤掉了已有记录: %v", names(got))
+	}
+}
+
+func TestLeaderboardSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "board.json")
+
+	lb, err := LoadLeaderboard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lb.Entries) != 0 {
+		t.Fatalf("文件不存在时应返回空排行榜，得到 %v", lb.Entries)
+	}
+
+	for i := 0; i < MaxEntries+3; i++ {
+		lb.Add(entry(string(rune('a'+i)), "normal", 100+i%4, i))
+	}
+	lb.Add(entry("h", "hard", 50, 0))
+	if err := lb.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLeaderboard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Entries, lb.Entries) {
+		t.Errorf("读回的记录\n%v\n与保存的不同\n%v", loaded.Entries, lb.Entries)
+	}
+	want := names(lb.Top("normal", MaxEntries))
+	if got := names(loaded.Top("normal", MaxEntries)); !reflect.DeepEqual(got, want) {
+		t.Errorf("读回后 Top = %v，期望 %v", got, want)
+	}
+
+	// 读回后继续添加并保存，仍然只保留前 MaxEntries 条
+	loaded.Add(entry("new", "normal", 1000, 50))
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadLeaderboard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top := again.Top("normal", MaxEntries+5); len(top) != MaxEntries || top[0].Name != "new" {
+		t.Errorf("再次读回 Top = %v", names(top))
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("保存后临时文件应已被重命名")
+	}
+}
+
+func TestLoadLeaderboardCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.json")
+	os.WriteFile(path, []byte("{not json"), 0o644)
+	if _, err := LoadLeaderboard(path); err == nil {
+		t.Error("文件内容损坏时应返回错误")
+	}
+}
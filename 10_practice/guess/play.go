@@ -0,0 +1,60 @@
+package guess
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-learn/10_practice/i18n"
+)
+
+// Play 从in读取玩家输入、向out输出提示，直到游戏结束
+// 如果in是*bufio.Reader会直接使用，避免与调用方争抢缓冲区中的数据
+func Play(g *Game, in io.Reader, out io.Writer) error {
+	reader, ok := in.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(in)
+	}
+
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("guess.title"))
+	fmt.Fprintln(out, i18n.T("guess.intro", g.Min, g.Max))
+
+	for g.State() == Playing {
+		fmt.Fprint(out, i18n.N("guess.prompt", g.Remaining(), g.Attempts()+1, g.Remaining()))
+
+		input, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || input == "") {
+			fmt.Fprintln(out, i18n.T("common.read_error", err))
+			return err
+		}
+
+		guess, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil {
+			fmt.Fprintln(out, i18n.T("guess.invalid_number"))
+			continue
+		}
+
+		fb, err := g.Guess(guess)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+
+		switch fb {
+		case Correct:
+			fmt.Fprintln(out, i18n.T("guess.correct", g.Target()))
+			fmt.Fprintln(out, i18n.N("guess.used_attempts", g.Attempts(), g.Attempts()))
+		case TooLow:
+			fmt.Fprintln(out, i18n.T("guess.too_low"))
+		case TooHigh:
+			fmt.Fprintln(out, i18n.T("guess.too_high"))
+		}
+	}
+
+	if g.State() == Lost {
+		fmt.Fprintln(out, i18n.T("guess.lost", g.Target()))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package guess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+
+	"go-learn/10_practice/i18n"
+)
+
+// ErrInconsistent 反馈互相矛盾（例如先说"太小"又说"太大"）时返回
+var ErrInconsistent = i18n.Error("solver.inconsistent")
+
+// Solver 用二分查找猜数字，最多需要 OptimalAttempts(min, max) 次
+type Solver struct {
+	lo, hi int
+}
+
+// NewSolver 创建在[min, max]范围内猜数字的求解器
+func NewSolver(min, max int) *Solver {
+	return &Solver{lo: min, hi: max}
+}
+
+// Next 返回下一次应该猜的数字（当前范围的中点）
+func (s *Solver) Next() int {
+	return s.lo + (s.hi-s.lo)/2
+}
+
+// Feedback 根据对guess的反馈缩小范围
+func (s *Solver) Feedback(guess int, fb Feedback) error {
+	switch fb {
+	case TooLow:
+		s.lo = guess + 1
+	case TooHigh:
+		s.hi = guess - 1
+	case Correct:
+		s.lo, s.hi = guess, guess
+	}
+	if s.lo > s.hi {
+		return ErrInconsistent
+	}
+	return nil
+}
+
+// OptimalAttempts 返回二分查找在N=max-min+1个数中保证猜中所需的次数，
+// 即 ceil(log2(N+1))
+func OptimalAttempts(min, max int) int {
+	return bits.Len(uint(max - min + 1))
+}
+
+// Solve 让求解器与游戏引擎对战，返回猜中所用的次数；未猜中时返回错误
+func Solve(g *Game) (int, error) {
+	s := NewSolver(g.Min, g.Max)
+	for g.State() == Playing {
+		n := s.Next()
+		fb, err := g.Guess(n)
+		if err != nil {
+			return g.Attempts(), err
+		}
+		if err := s.Feedback(n, fb); err != nil {
+			return g.Attempts(), err
+		}
+	}
+
+	if g.State() != Won {
+		return g.Attempts(), errors.New(i18n.N("solver.not_solved", g.MaxAttempts, g.MaxAttempts))
+	}
+	return g.Attempts(), nil
+}
+
+// Distribution 对[min, max]中的每个目标数字运行求解器，
+// 返回"猜中所需次数 -> 目标数字个数"的分布
+func Distribution(min, max int) (map[int]int, error) {
+	dist := make(map[int]int)
+	limit := OptimalAttempts(min, max)
+
+	for target := min; target <= max; target++ {
+		g := &Game{Min: min, Max: max, MaxAttempts: limit, target: target}
+		n, err := Solve(g)
+		if err != nil {
+			return dist, fmt.Errorf("%s: %w", i18n.T("solver.target", target), err)
+		}
+		dist[n]++
+	}
+	return dist, nil
+}
+
+// PlaySolver 电脑猜玩家心里想的数字，玩家用 大/小/对 回答
+func PlaySolver(min, max int, in io.Reader, out io.Writer) error {
+	reader, ok := in.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(in)
+	}
+
+	s := NewSolver(min, max)
+	limit := OptimalAttempts(min, max)
+	fmt.Fprintln(out, i18n.N("solver.intro", limit, min, max, limit))
+	fmt.Fprintln(out, i18n.T("solver.instructions"))
+
+	for attempts := 1; ; {
+		n := s.Next()
+		fmt.Fprint(out, i18n.T("solver.ask", attempts, n))
+
+		input, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || input == "") {
+			return err
+		}
+
+		var fb Feedback
+		switch strings.TrimSpace(input) {
+		case "大", "h":
+			fb = TooHigh
+		case "小", "l":
+			fb = TooLow
+		case "对", "y":
+			fmt.Fprintln(out, i18n.N("solver.won", attempts, attempts))
+			return nil
+		default:
+			fmt.Fprintln(out, i18n.T("solver.bad_answer"))
+			continue
+		}
+
+		if err := s.Feedback(n, fb); err != nil {
+			fmt.Fprintln(out, err)
+			return err
+		}
+		attempts++
+	}
+}
@@ -0,0 +1,100 @@
+package guess
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-learn/10_practice/i18n"
+)
+
+func TestSolveWithinOptimal(t *testing.T) {
+	dist, err := Distribution(1, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := OptimalAttempts(1, 1000)
+	total := 0
+	for attempts, n := range dist {
+		if attempts > limit {
+			t.Errorf("有 %d 个目标用了 %d 次，超过上限 %d", n, attempts, limit)
+		}
+		total += n
+	}
+	if total != 1000 {
+		t.Errorf("分布中共 %d 个目标，期望 1000", total)
+	}
+}
+
+func TestOptimalAttempts(t *testing.T) {
+	tests := []struct{ min, max, want int }{
+		{1, 1, 1},
+		{1, 3, 2},
+		{1, 100, 7},
+		{1, 1000, 10},
+	}
+	for _, tt := range tests {
+		if got := OptimalAttempts(tt.min, tt.max); got != tt.want {
+			t.Errorf("OptimalAttempts(%d, %d) = %d，期望 %d", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestSolverInconsistent(t *testing.T) {
+	s := NewSolver(1, 10)
+	if err := s.Feedback(5, TooLow); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Feedback(6, TooHigh); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("矛盾的反馈返回 %v，期望 ErrInconsistent", err)
+	}
+}
+
+func TestPlaySolver(t *testing.T) {
+	// 心里想的是 30：50 大、25 小、37 大、31 大、28 小、29 小、30 对
+	input := "h\nl\n?\nh\nh\nl\nl\ny\n"
+	var out strings.Builder
+	if err := PlaySolver(1, 100, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("PlaySolver: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	// 无效的回答不计入次数，第7次猜30时猜中
+	if want := i18n.T("solver.ask", 7, 30) + i18n.N("solver.won", 7, 7) + "\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("输出应以 %q 结尾:\n%s", want, got)
+	}
+	if n := strings.Count(got, i18n.T("solver.bad_answer")); n != 1 {
+		t.Errorf("无效回答的提示出现了 %d 次，期望 1 次:\n%s", n, got)
+	}
+}
+
+func BenchmarkSolver(b *testing.B) {
+	const min, max = 1, 1_000_000
+	limit := OptimalAttempts(min, max)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g := &Game{Min: min, Max: max, MaxAttempts: limit, target: min + i%(max-min+1)}
+		if _, err := Solve(g); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDistribution(b *testing.B) {
+	var dist map[int]int
+	for i := 0; i < b.N; i++ {
+		var err error
+		if dist, err = Distribution(1, 1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	// 报告所需次数的平均值和最大值，便于比较求解策略
+	total, targets, most := 0, 0, 0
+	for attempts, n := range dist {
+		total += attempts * n
+		targets += n
+		most = max(most, attempts)
+	}
+	b.ReportMetric(float64(total)/float64(targets), "mean-attempts")
+	b.ReportMetric(float64(most), "max-attempts")
+}
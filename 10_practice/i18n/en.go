@@ -0,0 +1,196 @@
+package i18n
+
+var enCatalog = Catalog{
+	// 程序与菜单
+	"app.welcome":           {Other: "🚀 Welcome to the Go practice exercises!"},
+	"app.tui_title":         {Other: "🚀 Go Practice Exercises"},
+	"app.unknown_exercise":  {Other: "Unknown exercise: %s (use -list to see all exercises)"},
+	"app.exercise_failed":   {Other: "Exercise %s failed: %v"},
+	"app.open_input_failed": {Other: "Cannot open input file: %v"},
+	"app.tui_failed":        {Other: "Cannot enter full-screen mode: %v"},
+	"app.tui_error":         {Other: "Terminal UI error: %v"},
+	"app.messages_ok":       {Other: "All messages are translated in every language"},
+	"app.messages_missing":  {One: "Found %d translation problem:", Other: "Found %d translation problems:"},
+	"flag.exercise":         {Other: "run the given exercise directly instead of showing the menu"},
+	"flag.input":            {Other: "read input from this file instead of stdin, for unattended runs"},
+	"flag.list":             {Other: "list all exercises"},
+	"flag.tui":              {Other: "use the full-screen terminal UI (arrow-key navigation); with -input, keys are read from the file"},
+	"flag.lang":             {Other: "interface language: zh or en (defaults to the LANG environment variable)"},
+	"flag.check_messages":   {Other: "check the message catalog for missing translations"},
+	"flag.log_level":        {Other: "diagnostic log level: debug, info, warn or error (written to stderr)"},
+	"flag.log_format":       {Other: "diagnostic log format: text or json"},
+	"app.bad_log_option":    {Other: "invalid log option: %v"},
+	"app.bad_log_level":     {Other: "unknown log level %q"},
+	"app.bad_log_format":    {Other: "unknown log format %q"},
+	"app.bad_config":        {Other: "invalid configuration:"},
+	"flag.seed":             {Other: "random seed, 0 means use the current time"},
+	"flag.record":           {Other: "record this session's input and output to a file"},
+	"flag.replay":           {Other: "replay a recorded session (or every .jsonl file in a directory) and check the output"},
+	"flag.config":           {Other: "configuration file (JSON or TOML-like); can also be set with PRACTICE_CONFIG"},
+	"app.record_failed":     {Other: "cannot record session: %v"},
+	"app.record_tui":        {Other: "cannot record the full-screen UI on a real terminal; use -input to read keys from a file"},
+	"app.close_failed":      {Other: "cannot close %s: %v"},
+	"app.replay_error":      {Other: "cannot replay sessions: %v"},
+	"app.replay_pass":       {Other: "PASS: %s"},
+	"app.replay_fail":       {Other: "FAIL: %s: %v"},
+	"app.replay_summary":    {Other: "%d sessions, %d failed"},
+	"flag.expr":             {Other: "expression for the calculator, e.g. \"2+3\" (with -exercise calculator)"},
+	"menu.title":            {Other: "Interactive Menu"},
+	"menu.choose_exercise":  {Other: "Choose an exercise:"},
+	"menu.quit_item":        {Other: "Quit"},
+	"menu.prompt":           {Other: "Choose (0-%d): "},
+	"menu.goodbye":          {Other: "Thanks for playing! Bye!"},
+	"menu.invalid":          {Other: "Invalid choice, please enter 0-%d"},
+	"menu.run_error":        {Other: "Exercise failed: %v"},
+	"menu.tui_status":       {Other: "↑/↓ select  Enter run  PgUp/PgDn scroll output  q quit"},
+	"common.read_error":     {Other: "Error reading input: %v"},
+	"common.choose":         {Other: "Choose: "},
+
+	// 练习名称
+	"exercise.students":   {Other: "Student manager"},
+	"exercise.downloader": {Other: "Concurrent downloader"},
+	"exercise.calculator": {Other: "Calculator"},
+	"exercise.guess":      {Other: "Guess the number"},
+	"exercise.fileproc":   {Other: "File processor"},
+	"exercise.solver":     {Other: "Computer guesses your number"},
+
+	// 学生管理系统
+	"students.demo":       {Other: "Student Manager Demo"},
+	"students.added":      {Other: "Student added: %s (ID: %d)"},
+	"students.not_found":  {Other: "No student with ID %d"},
+	"students.empty":      {Other: "No students yet"},
+	"students.list":       {Other: "Students"},
+	"students.col_name":   {Other: "Name"},
+	"students.col_age":    {Other: "Age"},
+	"students.col_grade":  {Other: "Grade"},
+	"students.find_error": {Other: "Lookup failed: %v"},
+	"students.found":      {Other: "Found student: %s, age: %d, grade: %.1f"},
+	"students.average":    {Other: "Class average grade: %.1f"},
+
+	// 并发下载器
+	"downloader.demo":       {Other: "Concurrent Downloader Demo"},
+	"downloader.failed":     {Other: "File %d failed: %v"},
+	"downloader.done":       {Other: "File %d done, size: %dKB, took %v"},
+	"downloader.all_done":   {Other: "All downloads finished in %v"},
+	"downloader.bad_url":    {Other: "malformed URL"},
+	"downloader.bad_status": {Other: "server returned status %d"},
+
+	// 计算器
+	"calc.demo":         {Other: "Calculator Demo"},
+	"calc.bad_format":   {Other: "malformed expression, expected: number operator number"},
+	"calc.bad_first":    {Other: "invalid first number: %v"},
+	"calc.bad_second":   {Other: "invalid second number: %v"},
+	"calc.div_zero":     {Other: "division by zero"},
+	"calc.bad_operator": {Other: "unsupported operator: %s"},
+	"calc.error":        {Other: "%s = error: %v"},
+
+	// 猜数字游戏
+	"guess.title":             {Other: "Guess the Number"},
+	"guess.intro":             {Other: "I'm thinking of a number between %d and %d. Can you guess it?"},
+	"guess.prompt":            {One: "Guess #%d (%d attempt left): ", Other: "Guess #%d (%d attempts left): "},
+	"guess.invalid_number":    {Other: "Please enter a valid number!"},
+	"guess.out_of_range":      {Other: "Please enter a number between %d and %d"},
+	"guess.correct":           {Other: "🎉 Congratulations! The number was %d"},
+	"guess.used_attempts":     {One: "You needed %d guess", Other: "You needed %d guesses"},
+	"guess.too_low":           {Other: "Too low! Try a bigger number"},
+	"guess.too_high":          {Other: "Too high! Try a smaller number"},
+	"guess.lost":              {Other: "😢 Game over! The answer was %d"},
+	"guess.game_over":         {Other: "the game is already over"},
+	"guess.bad_range":         {Other: "invalid number range: %d-%d"},
+	"guess.bad_attempts":      {Other: "attempts must be at least 1, got %d"},
+	"guess.state.playing":     {Other: "playing"},
+	"guess.state.won":         {Other: "won"},
+	"guess.state.lost":        {Other: "lost"},
+	"guess.difficulty.easy":   {Other: "Easy"},
+	"guess.difficulty.normal": {Other: "Normal"},
+	"guess.difficulty.hard":   {Other: "Hard"},
+	"guess.choose_difficulty": {Other: "Choose a difficulty:"},
+	"guess.difficulty_item":   {One: "%d. %s (%d-%d, %d attempt)", Other: "%d. %s (%d-%d, %d attempts)"},
+	"guess.difficulty_prompt": {Other: "Choose (Enter for Normal): "},
+	"guess.score":             {Other: "Score: %d (time %v)"},
+	"guess.ask_name":          {Other: "Your name: "},
+	"guess.anonymous":         {Other: "Anonymous"},
+	"guess.load_failed":       {Other: "cannot read leaderboard"},
+	"guess.save_failed":       {Other: "Cannot save leaderboard: %v"},
+	"guess.leaderboard":       {Other: "Leaderboard (%s)"},
+	"guess.leaderboard_empty": {Other: "No records yet"},
+	"guess.leaderboard_row":   {One: "%d. %-10s %5d pts  %d guess  %v", Other: "%d. %-10s %5d pts  %d guesses  %v"},
+
+	// 电脑猜数字
+	"solver.title":        {Other: "Computer Guesses"},
+	"solver.mode_human":   {Other: "1. You think of a number, the computer guesses it"},
+	"solver.mode_stats":   {Other: "2. Show how many attempts the solver needs for every target"},
+	"solver.intro":        {One: "Think of a number between %d and %d; I'll find it in %d attempt!", Other: "Think of a number between %d and %d; I'll find it within %d attempts!"},
+	"solver.instructions": {Other: "Answer h (my guess is too high), l (too low) or y (correct)"},
+	"solver.ask":          {Other: "Attempt %d: is it %d? "},
+	"solver.won":          {One: "🎉 Got it in %d attempt!", Other: "🎉 Got it in %d attempts!"},
+	"solver.bad_answer":   {Other: "Please answer h, l or y"},
+	"solver.inconsistent": {Other: "your answers contradict each other; no number is left in range"},
+	"solver.not_solved":   {One: "not solved within %d attempt", Other: "not solved within %d attempts"},
+	"solver.target":       {Other: "target %d"},
+	"solver.failed":       {Other: "%s: solver failed: %v"},
+	"solver.summary":      {Other: "%s (%d-%d): at most %d attempts, %.2f on average"},
+	"solver.dist_row":     {One: "  %2d attempts: %4d target", Other: "  %2d attempts: %4d targets"},
+
+	// 文件处理器
+	"fileproc.demo":          {Other: "File Processor Demo"},
+	"fileproc.top":           {Other: "Word frequencies (top %d):"},
+	"fileproc.bigrams":       {Other: "Bigrams (top %d):"},
+	"fileproc.most_frequent": {One: "Most frequent word: '%s' (%d time)", Other: "Most frequent word: '%s' (%d times)"},
+	"fileproc.most_char":     {One: "Most frequent single character: '%s' (%d time)", Other: "Most frequent single character: '%s' (%d times)"},
+	"fileproc.dir_error":     {Other: "Errors while counting the directory: %v"},
+	"fileproc.dir_summary":   {One: "%d distinct words in the current directory, most frequent: '%s' (%d time)", Other: "%d distinct words in the current directory, most frequent: '%s' (%d times)"},
+
+	// 配置
+	"config.field":                {Other: "setting %s: %s"},
+	"config.field_in_file":        {Other: "%s: setting %s: %s"},
+	"config.unknown_key":          {Other: "unknown setting"},
+	"config.wrong_type":           {Other: "expected %s, got %s"},
+	"config.type.string":          {Other: "a string"},
+	"config.type.int":             {Other: "an integer"},
+	"config.type.number":          {Other: "a number"},
+	"config.type.bool":            {Other: "a boolean"},
+	"config.type.table":           {Other: "a table"},
+	"config.type.array":           {Other: "an array"},
+	"config.env":                  {Other: "environment variable %s: %v"},
+	"config.file_only":            {Other: "this setting can only be set in a config file"},
+	"config.bad_difficulty":       {Other: "must be easy, normal or hard, got %q"},
+	"config.negative":             {Other: "must not be negative, got %v"},
+	"config.not_finite":           {Other: "must be a finite number, got %v"},
+	"config.no_urls":              {Other: "at least one URL is required"},
+	"config.bad_url":              {Other: "not a valid http(s) URL: %q"},
+	"config.empty_expression":     {Other: "expression must not be empty"},
+	"config.empty_name":           {Other: "name must not be empty"},
+	"config.out_of_range":         {Other: "must be between %v and %v, got %v"},
+	"config.toml.line":            {Other: "line %d: %s"},
+	"config.toml.bad_array_table": {Other: "malformed array of tables: %s"},
+	"config.toml.type_conflict":   {Other: "%s is already defined as another type"},
+	"config.toml.bad_table":       {Other: "malformed table header: %s"},
+	"config.toml.duplicate_table": {Other: "table [%s] is defined twice"},
+	"config.toml.want_key_value":  {Other: "expected key = value: %s"},
+	"config.toml.duplicate_key":   {Other: "%s is defined twice"},
+	"config.toml.missing_value":   {Other: "missing value"},
+	"config.toml.bad_string":      {Other: "malformed string: %s"},
+	"config.toml.unclosed_array":  {Other: "array is missing its closing bracket: %s"},
+	"config.toml.bad_value":       {Other: "unrecognized value: %s"},
+	"config.toml.unclosed_string": {Other: "string is missing its closing quote: %s"},
+	"config.toml.missing_comma":   {Other: "missing comma between array elements: %s"},
+
+	// 练习注册表
+	"exercise.duplicate": {Other: "exercise %q is already registered"},
+
+	// 会话回放
+	"session.mismatch": {Other: "output differs at line %d:\n  want: %q\n  got:  %q"},
+
+	// 消息目录检查
+	"i18n.missing_key":   {Other: "[%s] missing key %s"},
+	"i18n.extra_key":     {Other: "[%s] extra key %s"},
+	"i18n.verb_mismatch": {Other: "[%s] %s has a different number of format arguments than %s"},
+
+	// 文本统计命令
+	"textstats.header":      {Other: "lines\twords\tchars\tbytes\tsentences\tavg sentence\tdiversity\treadability\tfile\t"},
+	"textstats.flag_format": {Other: "output format: table or json"},
+	"textstats.flag_bigram": {Other: "count Han characters as overlapping bigrams instead of single characters"},
+	"textstats.bad_format":  {Other: "unsupported output format: %s"},
+	"textstats.json_failed": {Other: "writing JSON failed: %v"},
+}
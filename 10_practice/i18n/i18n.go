@@ -0,0 +1,190 @@
+// Package i18n 提供练习程序的多语言消息目录
+//
+// 消息通过键查找，支持中文和英文；英文等有单复数区别的语言可以为
+// 同一个键提供 One 和 Other 两种形式，用 N 按数量选择
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Locale 语言
+type Locale string
+
+const (
+	Chinese Locale = "zh"
+	English Locale = "en"
+)
+
+// Fallback 找不到翻译时使用的语言，也是检查缺失翻译时的基准
+const Fallback = Chinese
+
+// Message 一条消息，One为单数形式（可选），Other为默认形式
+type Message struct {
+	One   string
+	Other string
+}
+
+// Catalog 某种语言的全部消息
+type Catalog map[string]Message
+
+var catalogs = map[Locale]Catalog{
+	Chinese: zhCatalog,
+	English: enCatalog,
+}
+
+// current 当前语言，应在程序启动时设置一次，之后只读
+var current = Fallback
+
+// SetLocale 设置当前语言，不支持的语言返回false并保持不变
+func SetLocale(l Locale) bool {
+	if _, ok := catalogs[l]; !ok {
+		return false
+	}
+	current = l
+	return true
+}
+
+// Current 返回当前语言
+func Current() Locale {
+	return current
+}
+
+// Locales 返回所有支持的语言
+func Locales() []Locale {
+	locales := make([]Locale, 0, len(catalogs))
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// ParseLocale 解析 "en"、"en_US.UTF-8"、"zh-CN" 等形式的语言名称
+func ParseLocale(s string) (Locale, bool) {
+	s = strings.ToLower(s)
+	if i := strings.IndexAny(s, "_-.@"); i >= 0 {
+		s = s[:i]
+	}
+	l := Locale(s)
+	_, ok := catalogs[l]
+	return l, ok
+}
+
+// Detect 按 参数value、LC_ALL、LC_MESSAGES、LANG 的顺序确定语言
+func Detect(value string) Locale {
+	candidates := []string{value, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if l, ok := ParseLocale(c); ok {
+			return l
+		}
+	}
+	return Fallback
+}
+
+func lookup(key string) (Message, bool) {
+	if m, ok := catalogs[current][key]; ok {
+		return m, true
+	}
+	m, ok := catalogs[Fallback][key]
+	return m, ok
+}
+
+func format(text string, args []any) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// T 返回key对应的消息，args用于格式化；找不到时返回 "!key"，便于发现遗漏
+func T(key string, args ...any) string {
+	m, ok := lookup(key)
+	if !ok {
+		return "!" + key
+	}
+	return format(m.Other, args)
+}
+
+// N 与T相同，但按数量n选择单复数形式
+func N(key string, n int, args ...any) string {
+	m, ok := lookup(key)
+	if !ok {
+		return "!" + key
+	}
+	if n == 1 && m.One != "" {
+		return format(m.One, args)
+	}
+	return format(m.Other, args)
+}
+
+// localizedError 每次调用Error时才查找消息，因此可以在包初始化时创建
+type localizedError struct {
+	key string
+}
+
+func (e localizedError) Error() string {
+	return T(e.key)
+}
+
+// Error 创建一个消息随当前语言变化的错误，适合作为 errors.Is 比较用的哨兵错误
+func Error(key string) error {
+	return localizedError{key: key}
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// countVerbs 统计格式化动词的数量（不含%%）
+func countVerbs(s string) int {
+	n := 0
+	for _, v := range verbPattern.FindAllString(s, -1) {
+		if v != "%%" {
+			n++
+		}
+	}
+	return n
+}
+
+// Check 检查所有语言的消息目录：与基准语言相比缺失或多余的键，
+// 以及格式化动词数量不一致的翻译，返回发现的问题列表
+func Check() []string {
+	var problems []string
+	base := catalogs[Fallback]
+
+	for _, l := range Locales() {
+		if l == Fallback {
+			continue
+		}
+		c := catalogs[l]
+
+		for key, bm := range base {
+			m, ok := c[key]
+			if !ok {
+				problems = append(problems, T("i18n.missing_key", l, key))
+				continue
+			}
+			want := countVerbs(bm.Other)
+			for _, text := range []string{m.One, m.Other} {
+				if text != "" && countVerbs(text) != want {
+					problems = append(problems, T("i18n.verb_mismatch", l, key, Fallback))
+					break
+				}
+			}
+		}
+		for key := range c {
+			if _, ok := base[key]; !ok {
+				problems = append(problems, T("i18n.extra_key", l, key))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,128 @@
+package i18n
+
+import "testing"
+
+// withLocale 在测试期间切换语言，结束时恢复
+func withLocale(t *testing.T, l Locale) {
+	t.Helper()
+	saved := Current()
+	if !SetLocale(l) {
+		t.Fatalf("不支持的语言 %s", l)
+	}
+	t.Cleanup(func() { SetLocale(saved) })
+}
+
+// TestCatalogsComplete 与 -check-messages 相同：所有语言的键和格式化参数必须与基准语言一致
+func TestCatalogsComplete(t *testing.T) {
+	for _, p := range Check() {
+		t.Error(p)
+	}
+}
+
+func TestCheckFindsProblems(t *testing.T) {
+	withLocale(t, Chinese)
+	saved := catalogs[English]
+	t.Cleanup(func() { catalogs[English] = saved })
+
+	c := Catalog{"only.en": {Other: "x"}}
+	for key, m := range saved {
+		c[key] = m
+	}
+	delete(c, "app.welcome")
+	c["menu.prompt"] = Message{Other: "Choose: "}
+	catalogs[English] = c
+
+	want := []string{
+		"[en] menu.prompt 的格式化参数数量与 zh 不一致",
+		"[en] 多余的键 only.en",
+		"[en] 缺少键 app.welcome",
+	}
+	got := Check()
+	if len(got) != len(want) {
+		t.Fatalf("Check() = %q，期望 %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Check()[%d] = %q，期望 %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestN(t *testing.T) {
+	withLocale(t, English)
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "You needed 0 guesses"},
+		{1, "You needed 1 guess"},
+		{2, "You needed 2 guesses"},
+	}
+	for _, tt := range tests {
+		if got := N("guess.used_attempts", tt.n, tt.n); got != tt.want {
+			t.Errorf("N(%d) = %q，期望 %q", tt.n, got, tt.want)
+		}
+	}
+
+	// 中文没有单复数形式，总是使用 Other
+	SetLocale(Chinese)
+	if got, want := N("guess.used_attempts", 1, 1), T("guess.used_attempts", 1); got != want {
+		t.Errorf("中文 N(1) = %q，期望 %q", got, want)
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	withLocale(t, English)
+	saved := catalogs[English]
+	t.Cleanup(func() { catalogs[English] = saved })
+	catalogs[English] = Catalog{}
+
+	if got, want := T("menu.invalid", 3), "无效选择，请输入 0-3"; got != want {
+		t.Errorf("缺少翻译时 T = %q，期望回退到中文 %q", got, want)
+	}
+	if got := T("no.such.key"); got != "!no.such.key" {
+		t.Errorf("不存在的键 T = %q，期望 \"!no.such.key\"", got)
+	}
+	if got := N("no.such.key", 1); got != "!no.such.key" {
+		t.Errorf("不存在的键 N = %q，期望 \"!no.such.key\"", got)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Locale
+		ok   bool
+	}{
+		{"en", English, true},
+		{"en_US.UTF-8", English, true},
+		{"EN-gb", English, true},
+		{"zh-CN", Chinese, true},
+		{"zh_TW@stroke", Chinese, true},
+		{"fr_FR", "fr", false},
+		{"C", "c", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLocale(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseLocale(%q) = %q, %v，期望 %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "fr_FR")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := Detect(""); got != English {
+		t.Errorf("Detect 跳过不支持的 LC_MESSAGES 后 = %s，期望 en", got)
+	}
+	if got := Detect("zh"); got != Chinese {
+		t.Errorf("参数优先于环境变量，Detect = %s，期望 zh", got)
+	}
+	t.Setenv("LANG", "")
+	if got := Detect(""); got != Fallback {
+		t.Errorf("无可用设置时 Detect = %s，期望 %s", got, Fallback)
+	}
+}
@@ -0,0 +1,196 @@
+package i18n
+
+var zhCatalog = Catalog{
+	// 程序与菜单
+	"app.welcome":           {Other: "🚀 欢迎来到Go语言实战练习！"},
+	"app.tui_title":         {Other: "🚀 Go语言实战练习"},
+	"app.unknown_exercise":  {Other: "未知的练习: %s（使用 -list 查看所有练习）"},
+	"app.exercise_failed":   {Other: "练习 %s 运行出错: %v"},
+	"app.open_input_failed": {Other: "打开输入文件失败: %v"},
+	"app.tui_failed":        {Other: "无法进入全屏模式: %v"},
+	"app.tui_error":         {Other: "终端界面出错: %v"},
+	"app.messages_ok":       {Other: "所有语言的消息都已完整翻译"},
+	"app.messages_missing":  {Other: "发现 %d 个翻译问题:"},
+	"flag.exercise":         {Other: "直接运行指定的练习，不进入菜单"},
+	"flag.input":            {Other: "从文件读取输入（代替标准输入），便于无人值守运行"},
+	"flag.list":             {Other: "列出所有练习"},
+	"flag.tui":              {Other: "使用全屏终端界面（方向键选择），配合 -input 时从文件读取按键"},
+	"flag.lang":             {Other: "界面语言: zh 或 en（默认根据 LANG 环境变量）"},
+	"flag.check_messages":   {Other: "检查消息目录中缺失的翻译"},
+	"flag.log_level":        {Other: "诊断日志级别: debug、info、warn 或 error（输出到标准错误）"},
+	"flag.log_format":       {Other: "诊断日志格式: text 或 json"},
+	"app.bad_log_option":    {Other: "日志参数错误: %v"},
+	"app.bad_log_level":     {Other: "未知的日志级别 %q"},
+	"app.bad_log_format":    {Other: "未知的日志格式 %q"},
+	"app.bad_config":        {Other: "配置错误:"},
+	"flag.seed":             {Other: "随机种子，0表示使用当前时间"},
+	"flag.record":           {Other: "把本次会话的输入输出录制到文件"},
+	"flag.replay":           {Other: "回放录制文件（或目录下所有 .jsonl 文件）并检查输出是否一致"},
+	"flag.config":           {Other: "配置文件路径（JSON或类TOML格式），也可用环境变量 PRACTICE_CONFIG 指定"},
+	"app.record_failed":     {Other: "无法录制会话: %v"},
+	"app.record_tui":        {Other: "全屏界面使用真实终端时无法录制，请配合 -input 从文件读取按键"},
+	"app.close_failed":      {Other: "关闭文件 %s 失败: %v"},
+	"app.replay_error":      {Other: "无法回放会话: %v"},
+	"app.replay_pass":       {Other: "通过: %s"},
+	"app.replay_fail":       {Other: "失败: %s: %v"},
+	"app.replay_summary":    {Other: "共 %d 个会话，%d 个失败"},
+	"flag.expr":             {Other: "计算器要计算的表达式，如 \"2+3\"（配合 -exercise calculator）"},
+	"menu.title":            {Other: "交互式菜单"},
+	"menu.choose_exercise":  {Other: "选择你想要尝试的练习:"},
+	"menu.quit_item":        {Other: "退出"},
+	"menu.prompt":           {Other: "请选择 (0-%d): "},
+	"menu.goodbye":          {Other: "感谢使用！再见!"},
+	"menu.invalid":          {Other: "无效选择，请输入 0-%d"},
+	"menu.run_error":        {Other: "练习运行出错: %v"},
+	"menu.tui_status":       {Other: "↑/↓ 选择  Enter 运行  PgUp/PgDn 滚动输出  q 退出"},
+	"common.read_error":     {Other: "读取输入错误: %v"},
+	"common.choose":         {Other: "请选择: "},
+
+	// 练习名称
+	"exercise.students":   {Other: "学生管理系统"},
+	"exercise.downloader": {Other: "并发下载器"},
+	"exercise.calculator": {Other: "计算器"},
+	"exercise.guess":      {Other: "猜数字游戏"},
+	"exercise.fileproc":   {Other: "文件处理器"},
+	"exercise.solver":     {Other: "电脑猜数字"},
+
+	// 学生管理系统
+	"students.demo":       {Other: "学生管理系统演示"},
+	"students.added":      {Other: "添加学生成功: %s (ID: %d)"},
+	"students.not_found":  {Other: "未找到ID为%d的学生"},
+	"students.empty":      {Other: "暂无学生信息"},
+	"students.list":       {Other: "学生列表"},
+	"students.col_name":   {Other: "姓名"},
+	"students.col_age":    {Other: "年龄"},
+	"students.col_grade":  {Other: "成绩"},
+	"students.find_error": {Other: "查找失败: %v"},
+	"students.found":      {Other: "找到学生: %s, 年龄: %d, 成绩: %.1f"},
+	"students.average":    {Other: "班级平均成绩: %.1f"},
+
+	// 并发下载器
+	"downloader.demo":       {Other: "并发下载器演示"},
+	"downloader.failed":     {Other: "文件 %d 下载失败: %v"},
+	"downloader.done":       {Other: "文件 %d 下载完成，大小: %dKB，耗时: %v"},
+	"downloader.all_done":   {Other: "所有下载完成，总耗时: %v"},
+	"downloader.bad_url":    {Other: "URL格式错误"},
+	"downloader.bad_status": {Other: "服务器返回状态码 %d"},
+
+	// 计算器
+	"calc.demo":         {Other: "计算器演示"},
+	"calc.bad_format":   {Other: "表达式格式错误，应为: 数字 操作符 数字"},
+	"calc.bad_first":    {Other: "第一个数字格式错误: %v"},
+	"calc.bad_second":   {Other: "第二个数字格式错误: %v"},
+	"calc.div_zero":     {Other: "除数不能为零"},
+	"calc.bad_operator": {Other: "不支持的操作符: %s"},
+	"calc.error":        {Other: "%s = 错误: %v"},
+
+	// 猜数字游戏
+	"guess.title":             {Other: "猜数字游戏"},
+	"guess.intro":             {Other: "我想了一个%d-%d之间的数字，你来猜猜看！"},
+	"guess.prompt":            {Other: "第%d次猜测 (剩余%d次): "},
+	"guess.invalid_number":    {Other: "请输入有效的数字！"},
+	"guess.out_of_range":      {Other: "请输入%d-%d之间的数字"},
+	"guess.correct":           {Other: "🎉 恭喜你！猜对了！数字就是 %d"},
+	"guess.used_attempts":     {Other: "你用了 %d 次猜测"},
+	"guess.too_low":           {Other: "太小了！再试试更大的数字"},
+	"guess.too_high":          {Other: "太大了！再试试更小的数字"},
+	"guess.lost":              {Other: "😢 游戏结束！正确答案是 %d"},
+	"guess.game_over":         {Other: "游戏已经结束"},
+	"guess.bad_range":         {Other: "数字范围无效: %d-%d"},
+	"guess.bad_attempts":      {Other: "猜测次数至少为1，实际为%d"},
+	"guess.state.playing":     {Other: "进行中"},
+	"guess.state.won":         {Other: "胜利"},
+	"guess.state.lost":        {Other: "失败"},
+	"guess.difficulty.easy":   {Other: "简单"},
+	"guess.difficulty.normal": {Other: "普通"},
+	"guess.difficulty.hard":   {Other: "困难"},
+	"guess.choose_difficulty": {Other: "选择难度:"},
+	"guess.difficulty_item":   {Other: "%d. %s (%d-%d, %d次机会)"},
+	"guess.difficulty_prompt": {Other: "请选择 (直接回车为普通): "},
+	"guess.score":             {Other: "本局得分: %d (用时 %v)"},
+	"guess.ask_name":          {Other: "请输入你的名字: "},
+	"guess.anonymous":         {Other: "匿名"},
+	"guess.load_failed":       {Other: "读取排行榜失败"},
+	"guess.save_failed":       {Other: "保存排行榜失败: %v"},
+	"guess.leaderboard":       {Other: "排行榜 (%s)"},
+	"guess.leaderboard_empty": {Other: "暂无记录"},
+	"guess.leaderboard_row":   {Other: "%d. %-10s %5d分  %d次  %v"},
+
+	// 电脑猜数字
+	"solver.title":        {Other: "电脑猜数字"},
+	"solver.mode_human":   {Other: "1. 你想一个数字，电脑来猜"},
+	"solver.mode_stats":   {Other: "2. 统计求解器猜中每个目标所需的次数"},
+	"solver.intro":        {Other: "请在心里想一个%d-%d之间的数字，我保证在%d次内猜中！"},
+	"solver.instructions": {Other: "请回答: 大（我猜大了）、小（我猜小了）或 对"},
+	"solver.ask":          {Other: "第%d次: 是 %d 吗? "},
+	"solver.won":          {Other: "🎉 我猜中了！用了 %d 次"},
+	"solver.bad_answer":   {Other: "请输入 大、小 或 对"},
+	"solver.inconsistent": {Other: "反馈前后矛盾，范围内已没有可能的数字"},
+	"solver.not_solved":   {Other: "%d次内没有猜中"},
+	"solver.target":       {Other: "目标 %d"},
+	"solver.failed":       {Other: "%s: 求解失败: %v"},
+	"solver.summary":      {Other: "%s (%d-%d): 最多需要 %d 次，平均 %.2f 次"},
+	"solver.dist_row":     {Other: "  %2d次: %4d 个目标"},
+
+	// 文件处理器
+	"fileproc.demo":          {Other: "文件处理器演示"},
+	"fileproc.top":           {Other: "词频统计 (前%d):"},
+	"fileproc.bigrams":       {Other: "二元词组 (前%d):"},
+	"fileproc.most_frequent": {Other: "出现最多的词: '%s' (出现 %d 次)"},
+	"fileproc.most_char":     {Other: "按单字统计出现最多的字: '%s' (出现 %d 次)"},
+	"fileproc.dir_error":     {Other: "统计目录时出现错误: %v"},
+	"fileproc.dir_summary":   {Other: "当前目录共 %d 个不同的词，出现最多的词: '%s' (出现 %d 次)"},
+
+	// 配置
+	"config.field":                {Other: "配置项 %s: %s"},
+	"config.field_in_file":        {Other: "%s: 配置项 %s: %s"},
+	"config.unknown_key":          {Other: "未知的配置项"},
+	"config.wrong_type":           {Other: "应为%s，实际为 %s"},
+	"config.type.string":          {Other: "字符串"},
+	"config.type.int":             {Other: "整数"},
+	"config.type.number":          {Other: "数字"},
+	"config.type.bool":            {Other: "布尔值"},
+	"config.type.table":           {Other: "表"},
+	"config.type.array":           {Other: "数组"},
+	"config.env":                  {Other: "环境变量 %s: %v"},
+	"config.file_only":            {Other: "该配置项只能在配置文件中设置"},
+	"config.bad_difficulty":       {Other: "应为 easy、normal 或 hard，实际为 %q"},
+	"config.negative":             {Other: "不能为负数，实际为 %v"},
+	"config.not_finite":           {Other: "必须是有限的数字，实际为 %v"},
+	"config.no_urls":              {Other: "至少需要一个URL"},
+	"config.bad_url":              {Other: "不是有效的http(s)地址: %q"},
+	"config.empty_expression":     {Other: "表达式不能为空"},
+	"config.empty_name":           {Other: "姓名不能为空"},
+	"config.out_of_range":         {Other: "应在%v-%v之间，实际为 %v"},
+	"config.toml.line":            {Other: "第%d行: %s"},
+	"config.toml.bad_array_table": {Other: "表数组格式错误: %s"},
+	"config.toml.type_conflict":   {Other: "%s 已定义为其他类型"},
+	"config.toml.bad_table":       {Other: "表格式错误: %s"},
+	"config.toml.duplicate_table": {Other: "表 [%s] 重复定义"},
+	"config.toml.want_key_value":  {Other: "应为 key = value: %s"},
+	"config.toml.duplicate_key":   {Other: "%s 重复定义"},
+	"config.toml.missing_value":   {Other: "缺少值"},
+	"config.toml.bad_string":      {Other: "字符串格式错误: %s"},
+	"config.toml.unclosed_array":  {Other: "数组缺少结束的方括号: %s"},
+	"config.toml.bad_value":       {Other: "无法识别的值: %s"},
+	"config.toml.unclosed_string": {Other: "字符串缺少结束引号: %s"},
+	"config.toml.missing_comma":   {Other: "数组元素之间缺少逗号: %s"},
+
+	// 练习注册表
+	"exercise.duplicate": {Other: "练习 %q 已经注册过"},
+
+	// 会话回放
+	"session.mismatch": {Other: "第%d行输出不一致:\n  期望: %q\n  实际: %q"},
+
+	// 消息目录检查
+	"i18n.missing_key":   {Other: "[%s] 缺少键 %s"},
+	"i18n.extra_key":     {Other: "[%s] 多余的键 %s"},
+	"i18n.verb_mismatch": {Other: "[%s] %s 的格式化参数数量与 %s 不一致"},
+
+	// 文本统计命令
+	"textstats.header":      {Other: "行\t词\t字符\t字节\t句子\t平均句长\t词汇多样性\t易读性\t文件\t"},
+	"textstats.flag_format": {Other: "输出格式: table 或 json"},
+	"textstats.flag_bigram": {Other: "汉字按相邻二元组而不是单字计词"},
+	"textstats.bad_format":  {Other: "不支持的输出格式: %s"},
+	"textstats.json_failed": {Other: "输出JSON失败: %v"},
+}
@@ -3,14 +3,61 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
 	"math/rand"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"go-learn/10_practice/config"
+	"go-learn/10_practice/downloader"
+	"go-learn/10_practice/exercise"
+	"go-learn/10_practice/fileproc"
+	"go-learn/10_practice/guess"
+	"go-learn/10_practice/i18n"
+	"go-learn/10_practice/session"
+	"go-learn/10_practice/tui"
+)
+
+// cfg 练习使用的配置，main 中从配置文件和环境变量加载
+var cfg = config.Default()
+
+// 练习共用的随机数生成器，由 -seed 决定，这样录制的会话可以被重现
+var (
+	rngMu sync.Mutex
+	rng   = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
+func seedRand(seed int64) {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	rng = rand.New(rand.NewSource(seed))
+}
+
+func randIntn(n int) int {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return rng.Intn(n)
+}
+
+// newRandSource 从共用生成器派生一个新的随机源
+func newRandSource() rand.Source {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return rand.NewSource(rng.Int63())
+}
+
 // 练习1: 学生管理系统
 type Student struct {
 	ID    int
@@ -19,15 +66,20 @@ type Student struct {
 	Grade float64
 }
 
+// StudentManager 的用户输出写到out，诊断信息写到logger
 type StudentManager struct {
 	students []Student
 	nextID   int
+	out      io.Writer
+	logger   *slog.Logger
 }
 
-func NewStudentManager() *StudentManager {
+func NewStudentManager(out io.Writer) *StudentManager {
 	return &StudentManager{
 		students: make([]Student, 0),
 		nextID:   1,
+		out:      out,
+		logger:   slog.Default().With("component", "students"),
 	}
 }
 
@@ -40,7 +92,8 @@ func (sm *StudentManager) AddStudent(name string, age int, grade float64) {
 	}
 	sm.students = append(sm.students, student)
 	sm.nextID++
-	fmt.Printf("添加学生成功: %s (ID: %d)\n", name, student.ID)
+	sm.logger.Debug("添加学生", "id", student.ID, "name", name, "age", age, "grade", grade)
+	fmt.Fprintln(sm.out, i18n.T("students.added", name, student.ID))
 }
 
 func (sm *StudentManager) FindStudent(id int) (*Student, error) {
@@ -49,21 +102,23 @@ func (sm *StudentManager) FindStudent(id int) (*Student, error) {
 			return &sm.students[i], nil
 		}
 	}
-	return nil, fmt.Errorf("未找到ID为%d的学生", id)
+	sm.logger.Warn("查找的学生不存在", "id", id, "total", len(sm.students))
+	return nil, errors.New(i18n.T("students.not_found", id))
 }
 
 func (sm *StudentManager) ListAllStudents() {
 	if len(sm.students) == 0 {
-		fmt.Println("暂无学生信息")
+		fmt.Fprintln(sm.out, i18n.T("students.empty"))
 		return
 	}
 
-	fmt.Println("\n=== 学生列表 ===")
-	fmt.Printf("%-4s %-10s %-4s %-6s\n", "ID", "姓名", "年龄", "成绩")
-	fmt.Println(strings.Repeat("-", 30))
+	fmt.Fprintf(sm.out, "\n=== %s ===\n", i18n.T("students.list"))
+	fmt.Fprintf(sm.out, "%-4s %-10s %-4s %-6s\n", "ID",
+		i18n.T("students.col_name"), i18n.T("students.col_age"), i18n.T("students.col_grade"))
+	fmt.Fprintln(sm.out, strings.Repeat("-", 30))
 
 	for _, student := range sm.students {
-		fmt.Printf("%-4d %-10s %-4d %-6.1f\n",
+		fmt.Fprintf(sm.out, "%-4d %-10s %-4d %-6.1f\n",
 			student.ID, student.Name, student.Age, student.Grade)
 	}
 }
@@ -81,57 +136,104 @@ func (sm *StudentManager) GetAverageGrade() float64 {
 }
 
 // 练习2: 简单的并发下载器
-func downloadFile(url string, id int) {
-	fmt.Printf("开始下载文件 %d: %s\n", id, url)
-
-	// 模拟下载时间
-	downloadTime := time.Duration(rand.Intn(3)+1) * time.Second
-	time.Sleep(downloadTime)
+// simulatedFetcher 返回模拟的下载函数：延迟和文件大小由seed和URL决定，
+// 与并发下载的调度顺序无关，因此相同的种子总是得到相同的结果
+func simulatedFetcher(seed int64) downloader.Fetcher {
+	return func(ctx context.Context, url string) (io.ReadCloser, error) {
+		h := fnv.New64a()
+		io.WriteString(h, url)
+		r := rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+
+		latency := time.Duration(r.Intn(3)+1) * 100 * time.Millisecond
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 
-	fmt.Printf("文件 %d 下载完成，耗时: %v\n", id, downloadTime)
+		size := (r.Intn(4) + 1) * 64 * 1024
+		return io.NopCloser(io.LimitReader(zeroReader{}, int64(size))), nil
+	}
 }
 
-func concurrentDownloader(urls []string) {
-	fmt.Println("\n=== 并发下载器演示 ===")
+type zeroReader struct{}
 
-	start := time.Now()
-	done := make(chan bool, len(urls))
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
 
-	// 启动并发下载
-	for i, url := range urls {
-		go func(url string, id int) {
-			downloadFile(url, id)
-			done <- true
-		}(url, i+1)
+func concurrentDownloader(ctx context.Context, urls []string, out io.Writer) {
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("downloader.demo"))
+
+	// 默认同一主机最多2个连接，每秒最多4个请求，总带宽512KB/s
+	d := downloader.New(downloader.Config{
+		MaxPerHost:        cfg.Downloader.MaxPerHost,
+		RequestsPerSecond: cfg.Downloader.RequestsPerSecond,
+		BytesPerSecond:    cfg.Downloader.BytesPerSecond,
+	}, simulatedFetcher(newRandSource().Int63()))
+	// 开始下载的先后取决于goroutine调度，写到诊断日志中，使标准输出保持确定
+	d.OnStart = func(id int, url string) {
+		slog.Info("开始下载", "id", id, "url", url)
 	}
 
-	// 等待所有下载完成
-	for i := 0; i < len(urls); i++ {
-		<-done
+	start := time.Now()
+	results := d.DownloadAll(ctx, urls)
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintln(out, i18n.T("downloader.failed", r.ID, r.Err))
+			continue
+		}
+		fmt.Fprintln(out, i18n.T("downloader.done",
+			r.ID, r.Bytes/1024, r.Duration.Round(time.Millisecond)))
 	}
 
-	fmt.Printf("所有下载完成，总耗时: %v\n", time.Since(start))
+	fmt.Fprintln(out, i18n.T("downloader.all_done", time.Since(start).Round(time.Millisecond)))
 }
 
 // 练习3: 简单的计算器
 type Calculator struct{}
 
+// splitExpression 把表达式拆成 数字、操作符、数字 三部分，操作符两边的空格可以省略
+func splitExpression(expression string) []string {
+	if parts := strings.Fields(expression); len(parts) == 3 {
+		return parts
+	}
+
+	expression = strings.TrimSpace(expression)
+	// 从第二个字符开始找操作符，跳过第一个数字的正负号
+	for i := 1; i < len(expression); i++ {
+		if !strings.ContainsRune("+-*/", rune(expression[i])) {
+			continue
+		}
+		left := strings.TrimSpace(expression[:i])
+		if last := left[len(left)-1]; last != '.' && (last < '0' || last > '9') {
+			continue
+		}
+		return []string{left, expression[i : i+1], strings.TrimSpace(expression[i+1:])}
+	}
+	return nil
+}
+
 func (c Calculator) Calculate(expression string) (float64, error) {
-	parts := strings.Fields(expression)
+	parts := splitExpression(expression)
 	if len(parts) != 3 {
-		return 0, fmt.Errorf("表达式格式错误，应为: 数字 操作符 数字")
+		return 0, errors.New(i18n.T("calc.bad_format"))
 	}
 
 	num1, err := strconv.ParseFloat(parts[0], 64)
 	if err != nil {
-		return 0, fmt.Errorf("第一个数字格式错误: %v", err)
+		return 0, errors.New(i18n.T("calc.bad_first", err))
 	}
 
 	operator := parts[1]
 
 	num2, err := strconv.ParseFloat(parts[2], 64)
 	if err != nil {
-		return 0, fmt.Errorf("第二个数字格式错误: %v", err)
+		return 0, errors.New(i18n.T("calc.bad_second", err))
 	}
 
 	switch operator {
@@ -143,173 +245,309 @@ func (c Calculator) Calculate(expression string) (float64, error) {
 		return num1 * num2, nil
 	case "/":
 		if num2 == 0 {
-			return 0, fmt.Errorf("除数不能为零")
+			return 0, errors.New(i18n.T("calc.div_zero"))
 		}
 		return num1 / num2, nil
 	default:
-		return 0, fmt.Errorf("不支持的操作符: %s", operator)
+		return 0, errors.New(i18n.T("calc.bad_operator", operator))
 	}
 }
 
-// 练习4: 猜数字游戏
-func guessNumberGame() {
-	fmt.Println("\n=== 猜数字游戏 ===")
-	fmt.Println("我想了一个1-100之间的数字，你来猜猜看！")
-
-	rand.Seed(time.Now().UnixNano())
-	target := rand.Intn(100) + 1
-	attempts := 0
-	maxAttempts := 7
-
-	reader := bufio.NewReader(os.Stdin)
-
-	for attempts < maxAttempts {
-		fmt.Printf("第%d次猜测 (剩余%d次): ", attempts+1, maxAttempts-attempts)
+// 练习4: 猜数字游戏（引擎见 guess 包）
+func readLine(reader *bufio.Reader) string {
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(input)
+}
 
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Println("读取输入错误:", err)
-			continue
+// difficulties 返回可选难度，配置了 guess.max_attempts 时覆盖每个难度的可猜次数
+func difficulties() []guess.Difficulty {
+	ds := make([]guess.Difficulty, len(guess.Difficulties))
+	copy(ds, guess.Difficulties)
+	if cfg.Guess.MaxAttempts > 0 {
+		for i := range ds {
+			ds[i].MaxAttempts = cfg.Guess.MaxAttempts
 		}
+	}
+	return ds
+}
 
-		input = strings.TrimSpace(input)
-		guess, err := strconv.Atoi(input)
-		if err != nil {
-			fmt.Println("请输入有效的数字！")
-			continue
-		}
+func chooseDifficulty(reader *bufio.Reader, out io.Writer) guess.Difficulty {
+	ds := difficulties()
+	fmt.Fprintln(out, "\n"+i18n.T("guess.choose_difficulty"))
+	for i, d := range ds {
+		fmt.Fprintln(out, i18n.N("guess.difficulty_item", d.MaxAttempts,
+			i+1, d.Label(), d.Min, d.Max, d.MaxAttempts))
+	}
+	fmt.Fprint(out, i18n.T("guess.difficulty_prompt"))
 
-		attempts++
+	n, err := strconv.Atoi(readLine(reader))
+	if err == nil && n >= 1 && n <= len(ds) {
+		return ds[n-1]
+	}
 
-		if guess == target {
-			fmt.Printf("🎉 恭喜你！猜对了！数字就是 %d\n", target)
-			fmt.Printf("你用了 %d 次猜测\n", attempts)
-			return
-		} else if guess < target {
-			fmt.Println("太小了！再试试更大的数字")
-		} else {
-			fmt.Println("太大了！再试试更小的数字")
+	// 输入无效或直接回车时使用配置的默认难度；配置经过校验，找不到时仅作保险退回第一项
+	for _, d := range ds {
+		if d.Name == cfg.Guess.Difficulty {
+			return d
 		}
 	}
-
-	fmt.Printf("😢 游戏结束！正确答案是 %d\n", target)
+	return ds[0]
 }
 
-// 练习5: 文件处理器
-type FileProcessor struct{}
+func guessNumberGame(reader *bufio.Reader, out io.Writer) error {
+	difficulty := chooseDifficulty(reader, out)
+	game, err := difficulty.NewGame(newRandSource())
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if err := guess.Play(game, reader, out); err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	path := cfg.Guess.Leaderboard
+	if path == "" {
+		path = guess.DefaultLeaderboardPath()
+	}
+	lb, err := guess.LoadLeaderboard(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("guess.load_failed"), err)
+	}
 
-func (fp FileProcessor) CountWords(content string) map[string]int {
-	wordCount := make(map[string]int)
+	if game.State() == guess.Won {
+		score := difficulty.Score(game, elapsed)
+		fmt.Fprintln(out, i18n.T("guess.score", score, elapsed.Round(time.Second)))
 
-	// 简单的分词（按空格分割）
-	words := strings.Fields(strings.ToLower(content))
+		fmt.Fprint(out, i18n.T("guess.ask_name"))
+		name := readLine(reader)
+		if name == "" {
+			name = i18n.T("guess.anonymous")
+		}
 
-	for _, word := range words {
-		// 去除标点符号
-		word = strings.Trim(word, ".,!?;:")
-		if word != "" {
-			wordCount[word]++
+		lb.Add(guess.Entry{
+			Name:       name,
+			Difficulty: difficulty.Name,
+			Score:      score,
+			Attempts:   game.Attempts(),
+			Elapsed:    elapsed,
+			PlayedAt:   time.Now(),
+		})
+		if err := lb.Save(); err != nil {
+			fmt.Fprintln(out, i18n.T("guess.save_failed", err))
 		}
 	}
 
-	return wordCount
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("guess.leaderboard", difficulty.Label()))
+	top := lb.Top(difficulty.Name, 5)
+	if len(top) == 0 {
+		fmt.Fprintln(out, i18n.T("guess.leaderboard_empty"))
+	}
+	for i, e := range top {
+		fmt.Fprintln(out, i18n.N("guess.leaderboard_row", e.Attempts,
+			i+1, e.Name, e.Score, e.Attempts, e.Elapsed.Round(time.Second)))
+	}
+	return nil
 }
 
-func (fp FileProcessor) FindMostFrequentWord(wordCount map[string]int) (string, int) {
-	if len(wordCount) == 0 {
-		return "", 0
+// 练习4扩展: 电脑用二分查找猜数字
+func solverGame(reader *bufio.Reader, out io.Writer) error {
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("solver.title"))
+	fmt.Fprintln(out, i18n.T("solver.mode_human"))
+	fmt.Fprintln(out, i18n.T("solver.mode_stats"))
+	fmt.Fprint(out, i18n.T("common.choose"))
+
+	if readLine(reader) == "1" {
+		return guess.PlaySolver(1, 100, reader, out)
 	}
 
-	maxWord := ""
-	maxCount := 0
+	for _, d := range guess.Difficulties {
+		dist, err := guess.Distribution(d.Min, d.Max)
+		if err != nil {
+			fmt.Fprintln(out, i18n.T("solver.failed", d.Label(), err))
+			continue
+		}
 
-	for word, count := range wordCount {
-		if count > maxCount {
-			maxWord = word
-			maxCount = count
+		total, sum := 0, 0
+		for attempts, count := range dist {
+			total += count
+			sum += attempts * count
+		}
+		fmt.Fprintln(out, "\n"+i18n.T("solver.summary",
+			d.Label(), d.Min, d.Max, guess.OptimalAttempts(d.Min, d.Max), float64(sum)/float64(total)))
+		for attempts := 1; attempts <= guess.OptimalAttempts(d.Min, d.Max); attempts++ {
+			fmt.Fprintln(out, i18n.N("solver.dist_row", dist[attempts], attempts, dist[attempts]))
 		}
 	}
-
-	return maxWord, maxCount
+	return nil
 }
 
-func demonstrateFileProcessor() {
-	fmt.Println("\n=== 文件处理器演示 ===")
+// 练习5: 文件处理器（见 fileproc 包）
+func demonstrateFileProcessor(out io.Writer) {
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("fileproc.demo"))
 
 	text := `Go语言是Google开发的开源编程语言。Go语言简洁、高效、并发。
 	Go语言适合构建网络服务。许多公司使用Go语言开发微服务。
 	学习Go语言让编程变得更加有趣。`
 
-	fp := FileProcessor{}
+	fp := fileproc.FileProcessor{}
 	wordCount := fp.CountWords(text)
 
-	fmt.Println("词频统计:")
-	for word, count := range wordCount {
-		fmt.Printf("  %s: %d\n", word, count)
+	fmt.Fprintln(out, i18n.T("fileproc.top", 10))
+	for _, wf := range fp.TopK(wordCount, 10) {
+		fmt.Fprintf(out, "  %s: %d\n", wf.Word, wf.Count)
+	}
+
+	fmt.Fprintln(out, "\n"+i18n.T("fileproc.bigrams", 3))
+	for _, wf := range fp.TopK(fp.CountNGrams(text, 2), 3) {
+		fmt.Fprintf(out, "  %s: %d\n", wf.Word, wf.Count)
 	}
 
 	mostFrequent, count := fp.FindMostFrequentWord(wordCount)
-	fmt.Printf("\n出现最多的词: '%s' (出现 %d 次)\n", mostFrequent, count)
+	fmt.Fprintln(out, "\n"+i18n.N("fileproc.most_frequent", count, mostFrequent, count))
+
+	// 按单字切分并去掉中文停用词
+	fp.Tokenizer = fileproc.UnicodeTokenizer{
+		HanMode:   fileproc.HanUnigram,
+		Stopwords: fileproc.ChineseStopwords,
+	}
+	mostFrequent, count = fp.FindMostFrequentWord(fp.CountWords(text))
+	fmt.Fprintln(out, i18n.N("fileproc.most_char", count, mostFrequent, count))
+
+	// 流式并发统计整个目录树
+	dirCount, err := fp.CountDir(".", 0)
+	if err != nil {
+		fmt.Fprintln(out, i18n.T("fileproc.dir_error", err))
+	}
+	dirWord, dirWordCount := fp.FindMostFrequentWord(dirCount)
+	fmt.Fprintln(out, i18n.N("fileproc.dir_summary", dirWordCount,
+		len(dirCount), dirWord, dirWordCount))
+}
+
+// 各练习的注册。新增练习只需实现 exercise.Exercise 并在这里注册
+type studentExercise struct{}
+
+func (studentExercise) Name() string        { return "students" }
+func (studentExercise) Description() string { return i18n.T("exercise.students") }
+func (studentExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	demonstrateStudentManager(out)
+	return nil
+}
+
+type downloaderExercise struct{}
+
+func (downloaderExercise) Name() string        { return "downloader" }
+func (downloaderExercise) Description() string { return i18n.T("exercise.downloader") }
+func (downloaderExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	concurrentDownloader(ctx, cfg.Downloader.URLs, out)
+	return nil
 }
 
-func interactiveMenu() {
-	fmt.Println("\n=== 交互式菜单 ===")
-	fmt.Println("选择你想要尝试的练习:")
-	fmt.Println("1. 学生管理系统")
-	fmt.Println("2. 并发下载器")
-	fmt.Println("3. 计算器")
-	fmt.Println("4. 猜数字游戏")
-	fmt.Println("5. 文件处理器")
-	fmt.Println("0. 退出")
+type calculatorExercise struct {
+	expr string // 通过 -expr 指定时只计算这一个表达式
+}
 
-	reader := bufio.NewReader(os.Stdin)
+func (*calculatorExercise) Name() string        { return "calculator" }
+func (*calculatorExercise) Description() string { return i18n.T("exercise.calculator") }
+func (e *calculatorExercise) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&e.expr, "expr", "", i18n.T("flag.expr"))
+}
+func (e *calculatorExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	if e.expr == "" {
+		demonstrateCalculator(out)
+		return nil
+	}
+
+	result, err := Calculator{}.Calculate(e.expr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s = %g\n", e.expr, result)
+	return nil
+}
+
+type guessExercise struct{}
+
+func (guessExercise) Name() string        { return "guess" }
+func (guessExercise) Description() string { return i18n.T("exercise.guess") }
+func (guessExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	return guessNumberGame(exercise.LineReader(in), out)
+}
+
+type fileProcessorExercise struct{}
+
+func (fileProcessorExercise) Name() string        { return "fileproc" }
+func (fileProcessorExercise) Description() string { return i18n.T("exercise.fileproc") }
+func (fileProcessorExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	demonstrateFileProcessor(out)
+	return nil
+}
+
+type solverExercise struct{}
+
+func (solverExercise) Name() string        { return "solver" }
+func (solverExercise) Description() string { return i18n.T("exercise.solver") }
+func (solverExercise) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	return solverGame(exercise.LineReader(in), out)
+}
+
+func init() {
+	exercise.Register(studentExercise{})
+	exercise.Register(downloaderExercise{})
+	exercise.Register(&calculatorExercise{})
+	exercise.Register(guessExercise{})
+	exercise.Register(fileProcessorExercise{})
+	exercise.Register(solverExercise{})
+}
+
+func interactiveMenu(ctx context.Context, reg *exercise.Registry, in io.Reader, out io.Writer) {
+	exercises := reg.All()
+
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("menu.title"))
+	fmt.Fprintln(out, i18n.T("menu.choose_exercise"))
+	for i, e := range exercises {
+		fmt.Fprintf(out, "%d. %s\n", i+1, e.Description())
+	}
+	fmt.Fprintf(out, "0. %s\n", i18n.T("menu.quit_item"))
+
+	reader := exercise.LineReader(in)
 
 	for {
-		fmt.Print("\n请选择 (0-5): ")
+		fmt.Fprint(out, "\n"+i18n.T("menu.prompt", len(exercises)))
 		input, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Println("读取输入错误:", err)
-			continue
+		if err != nil && (err != io.EOF || input == "") {
+			fmt.Fprintln(out, i18n.T("common.read_error", err))
+			return
 		}
 
 		choice := strings.TrimSpace(input)
-
-		switch choice {
-		case "1":
-			demonstrateStudentManager()
-		case "2":
-			urls := []string{
-				"https://example.com/file1.zip",
-				"https://example.com/file2.zip",
-				"https://example.com/file3.zip",
-				"https://example.com/file4.zip",
-			}
-			concurrentDownloader(urls)
-		case "3":
-			demonstrateCalculator()
-		case "4":
-			guessNumberGame()
-		case "5":
-			demonstrateFileProcessor()
-		case "0":
-			fmt.Println("感谢使用！再见!")
+		if choice == "0" {
+			fmt.Fprintln(out, i18n.T("menu.goodbye"))
 			return
-		default:
-			fmt.Println("无效选择，请输入 0-5")
+		}
+
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > len(exercises) {
+			fmt.Fprintln(out, i18n.T("menu.invalid", len(exercises)))
+			continue
+		}
+
+		if err := exercises[n-1].Run(ctx, reader, out); err != nil {
+			fmt.Fprintln(out, i18n.T("menu.run_error", err))
 		}
 	}
 }
 
-func demonstrateStudentManager() {
-	fmt.Println("\n=== 学生管理系统演示 ===")
+func demonstrateStudentManager(out io.Writer) {
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("students.demo"))
 
-	sm := NewStudentManager()
+	sm := NewStudentManager(out)
 
-	// 添加一些示例学生
-	sm.AddStudent("张三", 20, 85.5)
-	sm.AddStudent("李四", 19, 92.0)
-	sm.AddStudent("王五", 21, 78.5)
-	sm.AddStudent("赵六", 20, 88.0)
+	// 添加配置中的示例学生
+	for _, s := range cfg.Students {
+		sm.AddStudent(s.Name, s.Age, s.Grade)
+	}
 
 	// 显示所有学生
 	sm.ListAllStudents()
@@ -317,46 +555,365 @@ func demonstrateStudentManager() {
 	// 查找学生
 	student, err := sm.FindStudent(2)
 	if err != nil {
-		fmt.Printf("查找失败: %v\n", err)
+		fmt.Fprintln(out, i18n.T("students.find_error", err))
 	} else {
-		fmt.Printf("\n找到学生: %s, 年龄: %d, 成绩: %.1f\n",
-			student.Name, student.Age, student.Grade)
+		fmt.Fprintln(out, "\n"+i18n.T("students.found",
+			student.Name, student.Age, student.Grade))
 	}
 
 	// 计算平均成绩
 	avgGrade := sm.GetAverageGrade()
-	fmt.Printf("\n班级平均成绩: %.1f\n", avgGrade)
+	fmt.Fprintln(out, "\n"+i18n.T("students.average", avgGrade))
 }
 
-func demonstrateCalculator() {
-	fmt.Println("\n=== 计算器演示 ===")
+func demonstrateCalculator(out io.Writer) {
+	fmt.Fprintf(out, "\n=== %s ===\n", i18n.T("calc.demo"))
 
 	calc := Calculator{}
-	expressions := []string{
-		"10 + 5",
-		"20 - 8",
-		"6 * 7",
-		"15 / 3",
-		"10 / 0",  // 错误示例
-		"abc + 5", // 错误示例
-	}
-
-	for _, expr := range expressions {
+	for _, expr := range cfg.Calculator.Expressions {
 		result, err := calc.Calculate(expr)
 		if err != nil {
-			fmt.Printf("%s = 错误: %v\n", expr, err)
+			fmt.Fprintln(out, i18n.T("calc.error", expr, err))
 		} else {
-			fmt.Printf("%s = %.2f\n", expr, result)
+			fmt.Fprintf(out, "%s = %.2f\n", expr, result)
+		}
+	}
+}
+
+// tuiMenu 全屏终端界面版本的菜单
+func tuiMenu(ctx context.Context, reg *exercise.Registry, term tui.Terminal) error {
+	exercises := reg.All()
+	menu := &tui.Menu{Title: i18n.T("app.tui_title"), Status: i18n.T("menu.tui_status")}
+	for _, e := range exercises {
+		menu.Items = append(menu.Items, e.Description())
+	}
+
+	return tui.Run(term, menu, func(i int, in io.Reader, out io.Writer) error {
+		if err := exercises[i].Run(ctx, in, out); err != nil {
+			fmt.Fprintln(out, "\n"+i18n.T("menu.run_error", err))
+		}
+		return nil
+	})
+}
+
+// newLogger 创建写到w的诊断日志，format为text或json，level为debug/info/warn/error
+// 诊断日志与练习的用户输出分开：用户输出写到标准输出，日志写到标准错误
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, errors.New(i18n.T("app.bad_log_level", level))
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, errors.New(i18n.T("app.bad_log_format", format))
+}
+
+// recordedArgs 返回录制文件中保存的参数：显式设置过的参数（录制、回放参数除外），
+// 加上实际使用的随机种子和语言，保证回放时行为一致
+func recordedArgs(fs *flag.FlagSet, seed int64) []string {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "record", "replay", "seed", "lang":
+			return
 		}
+		args = append(args, "-"+f.Name+"="+f.Value.String())
+	})
+	return append(args,
+		"-seed="+strconv.FormatInt(seed, 10),
+		"-lang="+string(i18n.Current()))
+}
+
+// sessionFiles 展开path：目录则返回其中所有 .jsonl 文件
+func sessionFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
 	}
+	return filepath.Glob(filepath.Join(path, "*.jsonl"))
+}
+
+// replaySessions 用录制时的参数重新运行本程序并比较输出，返回退出码
+func replaySessions(path string, out io.Writer) int {
+	files, err := sessionFiles(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.replay_error", err))
+		return exitUsage
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.replay_error", err))
+		return exitError
+	}
+
+	failed := 0
+	for _, file := range files {
+		s, err := session.LoadFile(file)
+		if err == nil {
+			var got string
+			if got, err = runSession(exe, s); err == nil {
+				err = session.Compare(s.Output(), got, session.MaskDurations)
+			}
+		}
+
+		if err != nil {
+			failed++
+			fmt.Fprintln(out, i18n.T("app.replay_fail", file, err))
+			continue
+		}
+		fmt.Fprintln(out, i18n.T("app.replay_pass", file))
+	}
+
+	fmt.Fprintln(out, i18n.T("app.replay_summary", len(files), failed))
+	if failed > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// runSession 用会话的参数和输入运行exe，返回标准输出
+// 子进程在新建的临时目录中运行，HOME等目录也指向该目录，
+// 这样回放既不依赖当前目录的内容，也不会读写用户真实的排行榜
+func runSession(exe string, s *session.Session) (string, error) {
+	dir, err := os.MkdirTemp("", "practice-replay-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	var got bytes.Buffer
+	cmd := exec.Command(exe, s.Header.Args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(s.Input())
+	// 录制时的配置已经记录在参数中，不让当前环境变量影响回放结果
+	cmd.Env = append(withoutConfigEnv(os.Environ()),
+		"HOME="+dir, "XDG_CONFIG_HOME="+dir, "AppData="+dir)
+	cmd.Stdout = &got
+	// 退出码不参与比较，练习出错的提示同样会体现在输出中；
+	// 但子进程没能启动时输出为空，应报告真正的原因而不是输出不一致
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", err
+		}
+	}
+	return got.String(), nil
+}
+
+// withoutConfigEnv 去掉配置相关的环境变量
+func withoutConfigEnv(env []string) []string {
+	kept := env[:0:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, config.EnvPrefix) {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+// loadConfig 加载配置：默认值 -> 配置文件 -> 环境变量，最后统一校验
+func loadConfig(path string) (config.Config, error) {
+	c := config.Default()
+	if path != "" {
+		var err error
+		if c, err = config.Load(path); err != nil {
+			return c, err
+		}
+	}
+	if err := c.ApplyEnv(os.LookupEnv); err != nil {
+		return c, err
+	}
+	return c, c.Validate()
+}
+
+// 退出码
+const (
+	exitOK    = 0
+	exitError = 1 // 练习运行出错
+	exitUsage = 2 // 命令行参数错误
+)
+
+// runExercise 非交互地运行指定的练习
+func runExercise(ctx context.Context, reg *exercise.Registry, name string, in io.Reader, out io.Writer) int {
+	e, ok := reg.Lookup(name)
+	if !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("app.unknown_exercise", name))
+		return exitUsage
+	}
+
+	if err := e.Run(ctx, in, out); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.exercise_failed", name, err))
+		return exitError
+	}
+	return exitOK
 }
 
 func main() {
-	fmt.Println("🚀 欢迎来到Go语言实战练习！")
+	// 先根据环境变量选择语言，这样参数说明也能被翻译
+	i18n.SetLocale(i18n.Detect(""))
+
+	exerciseName := flag.String("exercise", "", i18n.T("flag.exercise"))
+	inputFile := flag.String("input", "", i18n.T("flag.input"))
+	list := flag.Bool("list", false, i18n.T("flag.list"))
+	useTUI := flag.Bool("tui", false, i18n.T("flag.tui"))
+	lang := flag.String("lang", "", i18n.T("flag.lang"))
+	checkMessages := flag.Bool("check-messages", false, i18n.T("flag.check_messages"))
+	logLevel := flag.String("log-level", "warn", i18n.T("flag.log_level"))
+	logFormat := flag.String("log-format", "text", i18n.T("flag.log_format"))
+	seed := flag.Int64("seed", 0, i18n.T("flag.seed"))
+	recordFile := flag.String("record", "", i18n.T("flag.record"))
+	replayPath := flag.String("replay", "", i18n.T("flag.replay"))
+	configFile := flag.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), i18n.T("flag.config"))
+	exercise.Default.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *lang != "" {
+		i18n.SetLocale(i18n.Detect(*lang))
+	}
+
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.bad_log_option", err))
+		os.Exit(exitUsage)
+	}
+	slog.SetDefault(logger)
+
+	if *checkMessages {
+		problems := i18n.Check()
+		if len(problems) == 0 {
+			fmt.Println(i18n.T("app.messages_ok"))
+			return
+		}
+		fmt.Println(i18n.N("app.messages_missing", len(problems), len(problems)))
+		for _, p := range problems {
+			fmt.Println("  " + p)
+		}
+		os.Exit(exitError)
+	}
+
+	if *configFile != "" {
+		// 来自环境变量的配置文件也写入录制参数，保证回放时使用同一份配置
+		flag.Set("config", *configFile)
+	}
+	cfg, err = loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("app.bad_config"))
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(os.Stderr, "  "+line)
+		}
+		os.Exit(exitUsage)
+	}
+
+	if *list {
+		for _, e := range exercise.Default.All() {
+			fmt.Printf("%-12s %s\n", e.Name(), e.Description())
+		}
+		return
+	}
+
+	if *replayPath != "" {
+		os.Exit(replaySessions(*replayPath, os.Stdout))
+	}
+
+	// os.Exit 不会执行 defer，打开的文件记录在files中，由exit在退出前显式关闭
+	var files []*os.File
+	var rec *session.Recorder
+	exit := func(code int) {
+		// 录制文件写入失败时留下的是不完整的黄金文件，不能当作成功
+		if rec != nil {
+			if err := rec.Err(); err != nil {
+				fmt.Fprintln(os.Stderr, i18n.T("app.record_failed", err))
+				code = exitError
+			}
+		}
+		for _, f := range files {
+			if err := f.Close(); err != nil && code == exitOK {
+				fmt.Fprintln(os.Stderr, i18n.T("app.close_failed", f.Name(), err))
+				code = exitError
+			}
+		}
+		os.Exit(code)
+	}
+
+	var in io.Reader = os.Stdin
+	var out io.Writer = os.Stdout
+	if *inputFile != "" {
+		f, err := os.Open(*inputFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("app.open_input_failed", err))
+			exit(exitUsage)
+		}
+		files = append(files, f)
+		in = f
+	}
 
 	// 设置随机种子
-	rand.Seed(time.Now().UnixNano())
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	seedRand(*seed)
+
+	if *recordFile != "" {
+		// 真实终端直接读写 os.Stdin/os.Stdout，绕过了录制，全屏界面只能录制来自文件的按键
+		if *useTUI && *inputFile == "" {
+			fmt.Fprintln(os.Stderr, i18n.T("app.record_tui"))
+			exit(exitUsage)
+		}
+		f, err := os.Create(*recordFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("app.record_failed", err))
+			exit(exitUsage)
+		}
+		files = append(files, f)
+
+		rec, err = session.NewRecorder(f, session.Header{Args: recordedArgs(flag.CommandLine, *seed)})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("app.record_failed", err))
+			exit(exitError)
+		}
+		in, out = rec.Input(in), rec.Output(out)
+	}
+
+	ctx := context.Background()
+	if *exerciseName != "" {
+		exit(runExercise(ctx, exercise.Default, *exerciseName, in, out))
+	}
+
+	if *useTUI {
+		// 指定了输入文件时按键从文件读取，不切换真实终端的模式
+		var term tui.Terminal = tui.NewStreamTerminal(in, out)
+		if *inputFile == "" {
+			raw, err := tui.Open(os.Stdin, os.Stdout)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, i18n.T("app.tui_failed", err))
+				exit(exitError)
+			}
+			term = raw
+		}
+		err := tuiMenu(ctx, exercise.Default, term)
+		if raw, ok := term.(*tui.RawTerminal); ok {
+			raw.Close()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("app.tui_error", err))
+			exit(exitError)
+		}
+		exit(exitOK)
+	}
+
+	fmt.Fprintln(out, i18n.T("app.welcome"))
 
 	// 启动交互式菜单
-	interactiveMenu()
+	interactiveMenu(ctx, exercise.Default, in, out)
+	exit(exitOK)
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"go-learn/10_practice/config"
+)
+
+// runMainEnv 设置后测试二进制直接作为练习程序运行，供回放会话时的子进程使用
+const runMainEnv = "GO_LEARN_RUN_PRACTICE_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(exitOK)
+	}
+	os.Exit(m.Run())
+}
+
+// TestReplaySessions 回放 testdata/sessions 中的所有黄金文件，输出与录制时不一致即失败
+func TestReplaySessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("回放会运行完整的练习，-short 时跳过")
+	}
+	t.Setenv(runMainEnv, "1")
+
+	var out strings.Builder
+	code := replaySessions("testdata/sessions", &out)
+	t.Log("\n" + out.String())
+	if code != exitOK {
+		t.Errorf("回放失败，退出码 %d", code)
+	}
+}
+
+func TestChooseDifficulty(t *testing.T) {
+	defer func(old config.Config) { cfg = old }(cfg)
+	cfg = config.Default()
+	cfg.Guess.Difficulty = "hard"
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1\n", "easy"},
+		{"3\n", "hard"},
+		{"\n", "hard"},  // 直接回车使用配置的默认难度
+		{"0\n", "hard"}, // 超出范围的编号不会越界
+		{"9\n", "hard"},
+		{"abc\n", "hard"},
+	}
+	for _, tt := range tests {
+		d := chooseDifficulty(bufio.NewReader(strings.NewReader(tt.input)), io.Discard)
+		if d.Name != tt.want {
+			t.Errorf("输入 %q 选择了 %s，期望 %s", tt.input, d.Name, tt.want)
+		}
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("调试信息")
+	logger.Info("开始下载", "id", 3, "url", "https://example.com/a")
+
+	// 低于级别的日志被丢弃，只剩一行JSON，消息和属性分开记录
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("输出不是单行JSON: %v\n%s", err, buf.String())
+	}
+	if entry["level"] != "INFO" || entry["msg"] != "开始下载" ||
+		entry["id"] != 3.0 || entry["url"] != "https://example.com/a" {
+		t.Errorf("日志 = %v", entry)
+	}
+
+	buf.Reset()
+	logger, err = newLogger(&buf, "WARN", "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("不应输出")
+	logger.Warn("磁盘空间不足", "free", "1GB")
+	if got := buf.String(); !strings.Contains(got, "level=WARN") ||
+		!strings.Contains(got, "free=1GB") || strings.Contains(got, "不应输出") {
+		t.Errorf("text 日志 = %q", got)
+	}
+
+	for _, c := range []struct{ level, format string }{
+		{"verbose", "text"},
+		{"info", "xml"},
+	} {
+		if _, err := newLogger(io.Discard, c.level, c.format); err == nil {
+			t.Errorf("newLogger(%q, %q) 应返回错误", c.level, c.format)
+		}
+	}
+}
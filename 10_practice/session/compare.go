@@ -0,0 +1,53 @@
+package session
+
+import (
+	"regexp"
+	"strings"
+
+	"go-learn/10_practice/i18n"
+)
+
+// durationPattern 匹配 time.Duration 的输出，如 1.5s、320ms、2m3.1s
+var durationPattern = regexp.MustCompile(`\b\d+(\.\d+)?(h|m|s|ms|µs|us|ns)(\d+(\.\d+)?(m|s|ms|µs|us|ns))*\b`)
+
+// MaskDurations 把输出中的时间长度替换为 <duration>，使含有耗时的输出可以比较
+func MaskDurations(s string) string {
+	return durationPattern.ReplaceAllString(s, "<duration>")
+}
+
+// Mismatch 回放输出与录制输出不一致
+type Mismatch struct {
+	Line int // 第一处不同的行号，从1开始
+	Want string
+	Got  string
+}
+
+func (m *Mismatch) Error() string {
+	return i18n.T("session.mismatch", m.Line, m.Want, m.Got)
+}
+
+// Compare 逐行比较want和got，normalize不为nil时先对两者做规范化
+// 不一致时返回 *Mismatch
+func Compare(want, got string, normalize func(string) string) error {
+	if normalize != nil {
+		want, got = normalize(want), normalize(got)
+	}
+	if want == got {
+		return nil
+	}
+
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; ; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return &Mismatch{Line: i + 1, Want: w, Got: g}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaskDurations(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"耗时 1.5s", "耗时 <duration>"},
+		{"took 320ms, then 12µs", "took <duration>, then <duration>"},
+		{"总计 2m3.1s 完成", "总计 <duration> 完成"},
+		{"1h2m3s", "<duration>"},
+		{"下载 3 个文件", "下载 3 个文件"},
+		{"file2s.txt 10mb", "file2s.txt 10mb"},
+	}
+	for _, tt := range tests {
+		if got := MaskDurations(tt.in); got != tt.want {
+			t.Errorf("MaskDurations(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		normalize func(string) string
+		line      int // 0表示一致
+		w, g      string
+	}{
+		{name: "相同", want: "a\nb\n", got: "a\nb\n"},
+		{name: "规范化后相同", want: "耗时 1s\n", got: "耗时 2.5ms\n", normalize: MaskDurations},
+		{name: "中间不同", want: "a\nb\nc", got: "a\nx\nc", line: 2, w: "b", g: "x"},
+		{name: "实际输出更短", want: "a\nb", got: "a", line: 2, w: "b"},
+		{name: "实际输出更长", want: "a", got: "a\nb", line: 2, g: "b"},
+		{name: "只差结尾换行", want: "a\n", got: "a", line: 2},
+		{name: "未规范化", want: "耗时 1s", got: "耗时 2s", line: 1, w: "耗时 1s", g: "耗时 2s"},
+	}
+	for _, tt := range tests {
+		err := Compare(tt.want, tt.got, tt.normalize)
+		if tt.line == 0 {
+			if err != nil {
+				t.Errorf("%s: Compare 返回 %v，期望一致", tt.name, err)
+			}
+			continue
+		}
+		var m *Mismatch
+		if !errors.As(err, &m) {
+			t.Errorf("%s: Compare 返回 %v，期望 *Mismatch", tt.name, err)
+			continue
+		}
+		if m.Line != tt.line || m.Want != tt.w || m.Got != tt.g {
+			t.Errorf("%s: Mismatch = %+v，期望第%d行 %q/%q", tt.name, *m, tt.line, tt.w, tt.g)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package session 录制交互式练习的输入输出，并在之后回放比对，用作黄金文件回归测试
+//
+// 录制文件为JSON Lines格式：第一行是 Header，之后每行一个 Event
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version 当前录制文件格式的版本
+const Version = 1
+
+// Header 录制文件头，记录重现这次会话所需的参数
+type Header struct {
+	Version  int       `json:"version"`
+	Args     []string  `json:"args"` // 录制时的命令行参数（不含录制参数本身）
+	Recorded time.Time `json:"recorded"`
+}
+
+// Event 一次输入或输出
+type Event struct {
+	Offset time.Duration `json:"t"`    // 相对录制开始的时间
+	Kind   string        `json:"kind"` // "in" 或 "out"
+	Data   string        `json:"data"`
+}
+
+// 事件类型
+const (
+	KindInput  = "in"
+	KindOutput = "out"
+)
+
+// Recorder 把输入输出事件实时写入录制文件，可被多个goroutine同时使用
+type Recorder struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+	err   error
+}
+
+// NewRecorder 创建录制器并写入文件头
+func NewRecorder(w io.Writer, h Header) (*Recorder, error) {
+	h.Version = Version
+	if h.Recorded.IsZero() {
+		h.Recorded = time.Now()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(h); err != nil {
+		return nil, err
+	}
+	return &Recorder{enc: enc, start: time.Now()}, nil
+}
+
+func (r *Recorder) record(kind string, p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	r.err = r.enc.Encode(Event{
+		Offset: time.Since(r.start),
+		Kind:   kind,
+		Data:   string(p),
+	})
+}
+
+// Err 返回写入录制文件时遇到的第一个错误
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+type recordingReader struct {
+	r   io.Reader
+	rec *Recorder
+}
+
+func (rr recordingReader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	rr.rec.record(KindInput, p[:n])
+	return n, err
+}
+
+type recordingWriter struct {
+	w   io.Writer
+	rec *Recorder
+}
+
+func (rw recordingWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	rw.rec.record(KindOutput, p[:n])
+	return n, err
+}
+
+// Input 返回一个读取in并录制读到内容的Reader
+func (r *Recorder) Input(in io.Reader) io.Reader {
+	return recordingReader{r: in, rec: r}
+}
+
+// Output 返回一个写入out并录制写入内容的Writer
+func (r *Recorder) Output(out io.Writer) io.Writer {
+	return recordingWriter{w: out, rec: r}
+}
+
+// Session 一次录制好的会话
+type Session struct {
+	Header Header
+	Events []Event
+}
+
+// Load 读取录制文件
+func Load(r io.Reader) (*Session, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	s := &Session{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line == 1 {
+			if err := json.Unmarshal(scanner.Bytes(), &s.Header); err != nil {
+				return nil, fmt.Errorf("第%d行: 文件头格式错误: %w", line, err)
+			}
+			if s.Header.Version != Version {
+				return nil, fmt.Errorf("不支持的录制文件版本 %d", s.Header.Version)
+			}
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, err)
+		}
+		s.Events = append(s.Events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if line == 0 {
+		return nil, fmt.Errorf("录制文件为空")
+	}
+	return s, nil
+}
+
+// LoadFile 读取path处的录制文件
+func LoadFile(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+func (s *Session) join(kind string) string {
+	var sb strings.Builder
+	for _, e := range s.Events {
+		if e.Kind == kind {
+			sb.WriteString(e.Data)
+		}
+	}
+	return sb.String()
+}
+
+// Input 返回会话中的全部输入
+func (s *Session) Input() string {
+	return s.join(KindInput)
+}
+
+// Output 返回会话中的全部输出
+func (s *Session) Output() string {
+	return s.join(KindOutput)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-learn/10_practice/fileproc"
+	"go-learn/10_practice/i18n"
+)
+
+// runCmd 运行命令，返回退出码、标准输出和标准错误；结束后恢复语言设置
+func runCmd(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	defer i18n.SetLocale(i18n.Current())
+
+	var stdout, stderr strings.Builder
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func decode(t *testing.T, out string) []fileStats {
+	t.Helper()
+	var results []fileStats
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("输出不是JSON: %v\n%s", err, out)
+	}
+	return results
+}
+
+func TestWordsDefaultToUnigrams(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		words int
+	}{
+		{"默认每字一词", []string{"-format=json"}, 4},
+		{"二元组", []string{"-format=json", "-bigram"}, 3},
+	}
+	for _, tt := range tests {
+		code, out, _ := runCmd(t, "语言简洁\n", tt.args...)
+		if code != exitOK {
+			t.Fatalf("%s: 退出码 %d", tt.name, code)
+		}
+		if got := decode(t, out)[0].Stats.Words; got != tt.words {
+			t.Errorf("%s: 4个字的短语算作 %d 个词，期望 %d", tt.name, got, tt.words)
+		}
+	}
+}
+
+func TestFleschOnlyForAlphabeticText(t *testing.T) {
+	dir := t.TempDir()
+	en := filepath.Join(dir, "en.txt")
+	zh := filepath.Join(dir, "zh.txt")
+	os.WriteFile(en, []byte("The cat sat on the mat. It was happy.\n"), 0o644)
+	os.WriteFile(zh, []byte("今天天气很好。我们去公园散步。\n"), 0o644)
+
+	code, out, _ := runCmd(t, "", "-format=json", en, zh)
+	if code != exitOK {
+		t.Fatalf("退出码 %d", code)
+	}
+	results := decode(t, out)
+	if len(results) != 2 || results[0].File != en || results[1].File != zh {
+		t.Fatalf("结果 = %+v", results)
+	}
+	if results[0].Stats.FleschReadingEase == nil {
+		t.Error("英文文本应计算Flesch分数")
+	}
+	if results[1].Stats.FleschReadingEase != nil {
+		t.Errorf("中文文本不应计算Flesch分数，得到 %v", *results[1].Stats.FleschReadingEase)
+	}
+	var raw []struct {
+		Stats map[string]any `json:"stats"`
+	}
+	json.Unmarshal([]byte(out), &raw)
+	if _, ok := raw[1].Stats["flesch_reading_ease"]; ok {
+		t.Error("中文文本的JSON中不应出现 flesch_reading_ease")
+	}
+
+	// 表格中用 - 表示不适用
+	_, table, _ := runCmd(t, "", zh)
+	lines := strings.Split(strings.TrimRight(table, "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], " -  ") {
+		t.Errorf("表格 =\n%s", table)
+	}
+}
+
+func TestTableLocalized(t *testing.T) {
+	tests := []struct {
+		lang, header string
+	}{
+		{"zh", "词汇多样性"},
+		{"en", "readability"},
+	}
+	for _, tt := range tests {
+		code, out, _ := runCmd(t, "Hello world.\n", "-lang="+tt.lang)
+		if code != exitOK {
+			t.Fatalf("-lang=%s: 退出码 %d", tt.lang, code)
+		}
+		header, row, _ := strings.Cut(out, "\n")
+		if !strings.Contains(header, tt.header) {
+			t.Errorf("-lang=%s: 表头 = %q，期望包含 %q", tt.lang, header, tt.header)
+		}
+		if fields := strings.Fields(row); len(fields) != 9 || fields[1] != "2" || fields[8] != "-" {
+			t.Errorf("-lang=%s: 数据行 = %q", tt.lang, row)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	code, _, errOut := runCmd(t, "", "-lang=en", "-format=xml")
+	if code != exitUsage || !strings.Contains(errOut, "unsupported output format: xml") {
+		t.Errorf("错误的格式: 退出码 %d，错误输出 %q", code, errOut)
+	}
+
+	if code, _, _ := runCmd(t, "", "-no-such-flag"); code != exitUsage {
+		t.Errorf("未知参数退出码 %d，期望 %d", code, exitUsage)
+	}
+
+	// 单个文件出错时其他文件照常统计，退出码为1
+	dir := t.TempDir()
+	ok := filepath.Join(dir, "ok.txt")
+	os.WriteFile(ok, []byte("one two\n"), 0o644)
+	missing := filepath.Join(dir, "missing.txt")
+	code, out, errOut := runCmd(t, "", missing, ok)
+	if code != exitError {
+		t.Errorf("文件不存在时退出码 %d，期望 %d", code, exitError)
+	}
+	if !strings.Contains(errOut, missing) {
+		t.Errorf("错误输出 %q 中没有提到 %s", errOut, missing)
+	}
+	if !strings.Contains(out, ok) || strings.Contains(out, missing) {
+		t.Errorf("表格 =\n%s", out)
+	}
+}
+
+// TestStdin 不指定文件时读取标准输入，结果与 FileProcessor.Stats 一致
+func TestStdin(t *testing.T) {
+	const text = "Go is fun.\nGo is fast!\n"
+	code, out, _ := runCmd(t, text, "-format=json")
+	if code != exitOK {
+		t.Fatalf("退出码 %d", code)
+	}
+	fp := fileproc.FileProcessor{Tokenizer: fileproc.UnicodeTokenizer{HanMode: fileproc.HanUnigram}}
+	want, _ := fp.Stats(strings.NewReader(text))
+	got := decode(t, out)[0]
+	if got.File != "-" || got.Stats.Lines != want.Lines || got.Stats.Words != want.Words ||
+		got.Stats.Sentences != want.Sentences || got.Stats.Unique != want.Unique {
+		t.Errorf("标准输入统计 = %+v，期望 %+v", got.Stats, want)
+	}
+}
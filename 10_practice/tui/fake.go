@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// FakeTerminal 用于测试的假终端：按顺序返回预设的按键，并记录所有输出
+type FakeTerminal struct {
+	Keys          []Key     // ReadKey依次返回的按键，用完后返回io.EOF
+	Input         io.Reader // Suspend后返回给练习读取的输入
+	Width, Height int
+
+	out       bytes.Buffer
+	suspended bool
+}
+
+func (f *FakeTerminal) Write(p []byte) (int, error) {
+	return f.out.Write(p)
+}
+
+// ReadKey 实现 Terminal 接口
+func (f *FakeTerminal) ReadKey() (Key, error) {
+	if len(f.Keys) == 0 {
+		return Key{}, io.EOF
+	}
+	k := f.Keys[0]
+	f.Keys = f.Keys[1:]
+	return k, nil
+}
+
+// Size 实现 Terminal 接口
+func (f *FakeTerminal) Size() (int, int) {
+	if f.Width == 0 || f.Height == 0 {
+		return 80, 24
+	}
+	return f.Width, f.Height
+}
+
+// Suspend 实现 Terminal 接口
+func (f *FakeTerminal) Suspend() (io.Reader, error) {
+	f.suspended = true
+	if f.Input == nil {
+		return strings.NewReader(""), nil
+	}
+	return f.Input, nil
+}
+
+// Resume 实现 Terminal 接口
+func (f *FakeTerminal) Resume() error {
+	f.suspended = false
+	return nil
+}
+
+// Suspended 报告终端当前是否处于暂停状态
+func (f *FakeTerminal) Suspended() bool {
+	return f.suspended
+}
+
+// Output 返回写入终端的全部内容
+func (f *FakeTerminal) Output() string {
+	return f.out.String()
+}
+
+// LastFrame 返回最后一次完整绘制的画面（不含清屏序列）
+func (f *FakeTerminal) LastFrame() string {
+	s := f.out.String()
+	if i := strings.LastIndex(s, clearScreen); i >= 0 {
+		s = s[i+len(clearScreen):]
+	}
+	return s
+}
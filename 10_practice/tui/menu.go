@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"go-learn/10_practice/i18n"
+)
+
+// Action 处理按键后菜单要求执行的动作
+type Action int
+
+const (
+	ActionNone   Action = iota
+	ActionSelect        // 运行当前选中的项
+	ActionQuit          // 退出菜单
+)
+
+// Menu 全屏菜单：上方是可选项，下方是可滚动的输出区
+type Menu struct {
+	Title    string
+	Items    []string
+	Selected int
+	Status   string // 底部的按键提示，为空时使用默认提示
+
+	output []string
+	scroll int // 输出区从底部向上滚动的行数
+	pane   int // 上次渲染时输出区的高度，用于翻页
+}
+
+// SetOutput 替换输出区的内容并滚动到底部
+func (m *Menu) SetOutput(text string) {
+	text = strings.ReplaceAll(text, "\r", "")
+	text = strings.ReplaceAll(text, "\t", "    ")
+	m.output = strings.Split(strings.TrimRight(text, "\n"), "\n")
+	m.scroll = 0
+}
+
+// HandleKey 根据按键更新菜单状态
+func (m *Menu) HandleKey(k Key) Action {
+	page := m.pane
+	if page < 1 {
+		page = 1
+	}
+
+	switch k.Code {
+	case KeyUp:
+		if m.Selected > 0 {
+			m.Selected--
+		}
+	case KeyDown:
+		if m.Selected < len(m.Items)-1 {
+			m.Selected++
+		}
+	case KeyPageUp:
+		m.setScroll(m.scroll + page)
+	case KeyPageDown:
+		m.setScroll(m.scroll - page)
+	case KeyHome:
+		m.setScroll(len(m.output))
+	case KeyEnd:
+		m.setScroll(0)
+	case KeyEnter:
+		if len(m.Items) > 0 {
+			return ActionSelect
+		}
+	case KeyEsc, KeyCtrlC:
+		return ActionQuit
+	case KeyRune:
+		switch r := k.Rune; {
+		case r == 'q':
+			return ActionQuit
+		case r == 'k':
+			return m.HandleKey(Key{Code: KeyUp})
+		case r == 'j':
+			return m.HandleKey(Key{Code: KeyDown})
+		case r >= '1' && r <= '9' && int(r-'1') < len(m.Items):
+			m.Selected = int(r - '1')
+			return ActionSelect
+		}
+	}
+	return ActionNone
+}
+
+func (m *Menu) setScroll(n int) {
+	max := len(m.output) - m.pane
+	if n > max {
+		n = max
+	}
+	if n < 0 {
+		n = 0
+	}
+	m.scroll = n
+}
+
+// Render 把整个界面绘制到w上
+func (m *Menu) Render(w io.Writer, width, height int) error {
+	var lines []string
+	lines = append(lines, bold+truncate(m.Title, width)+reset, "")
+	for i, item := range m.Items {
+		line := truncate(fmt.Sprintf("  %d. %s", i+1, item), width)
+		if i == m.Selected {
+			line = reverse + padRight(line, width) + reset
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, strings.Repeat("─", width))
+
+	// 输出区占用剩余的行，最后一行留给按键提示
+	m.pane = height - len(lines) - 1
+	if m.pane < 0 {
+		m.pane = 0
+	}
+	m.setScroll(m.scroll)
+
+	end := len(m.output) - m.scroll
+	start := end - m.pane
+	if start < 0 {
+		start = 0
+	}
+	for _, line := range m.output[start:end] {
+		lines = append(lines, truncate(line, width))
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	status := m.Status
+	if status == "" {
+		status = i18n.T("menu.tui_status")
+	}
+	if m.scroll > 0 {
+		status += fmt.Sprintf("  [↑%d]", m.scroll)
+	}
+	lines = append(lines, truncate(status, width))
+
+	var buf bytes.Buffer
+	buf.WriteString(clearScreen)
+	// raw模式保留了输出处理，"\n"会被终端转换为"\r\n"
+	buf.WriteString(strings.Join(lines, "\n"))
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Run 运行菜单事件循环，选中某项时暂停全屏模式并调用run，
+// run的输出会同时显示在终端上并保存到输出区
+func Run(t Terminal, m *Menu, run func(index int, in io.Reader, out io.Writer) error) error {
+	for {
+		width, height := t.Size()
+		if err := m.Render(t, width, height); err != nil {
+			return err
+		}
+
+		key, err := t.ReadKey()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch m.HandleKey(key) {
+		case ActionQuit:
+			return nil
+		case ActionSelect:
+			in, err := t.Suspend()
+			if err != nil {
+				return err
+			}
+
+			var captured bytes.Buffer
+			if err := run(m.Selected, in, io.MultiWriter(t, &captured)); err != nil {
+				fmt.Fprintf(&captured, "\n%s\n", i18n.T("menu.run_error", err))
+			}
+			m.SetOutput(captured.String())
+
+			if err := t.Resume(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runeWidth 返回字符在终端中占用的列数，中日韩文字和全角字符占两列
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0xFF01 && r <= 0xFF60) || (r >= 0x3000 && r <= 0x303F) || r >= 0x1F300 {
+		return 2
+	}
+	return 1
+}
+
+// truncate 把s截断到最多width列
+func truncate(s string, width int) string {
+	cols := 0
+	for i, r := range s {
+		cols += runeWidth(r)
+		if cols > width {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// padRight 用空格把s补齐到width列
+func padRight(s string, width int) string {
+	cols := 0
+	for _, r := range s {
+		cols += runeWidth(r)
+	}
+	if cols >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-cols)
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newMenu() *Menu {
+	return &Menu{Title: "练习", Items: []string{"计算器", "猜数字", "文件处理器"}}
+}
+
+func TestHandleKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []Key
+		selected int
+		action   Action
+	}{
+		{"向下", []Key{{Code: KeyDown}}, 1, ActionNone},
+		{"不会越过最后一项", []Key{{Code: KeyDown}, {Code: KeyDown}, {Code: KeyDown}}, 2, ActionNone},
+		{"不会越过第一项", []Key{{Code: KeyUp}}, 0, ActionNone},
+		{"vi按键", []Key{{Code: KeyRune, Rune: 'j'}, {Code: KeyRune, Rune: 'j'}, {Code: KeyRune, Rune: 'k'}}, 1, ActionNone},
+		{"回车运行", []Key{{Code: KeyDown}, {Code: KeyEnter}}, 1, ActionSelect},
+		{"数字直接运行", []Key{{Code: KeyRune, Rune: '3'}}, 2, ActionSelect},
+		{"超出范围的数字", []Key{{Code: KeyRune, Rune: '9'}}, 0, ActionNone},
+		{"q退出", []Key{{Code: KeyRune, Rune: 'q'}}, 0, ActionQuit},
+		{"Esc退出", []Key{{Code: KeyEsc}}, 0, ActionQuit},
+		{"Ctrl+C退出", []Key{{Code: KeyCtrlC}}, 0, ActionQuit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMenu()
+			var action Action
+			for _, k := range tt.keys {
+				action = m.HandleKey(k)
+			}
+			if m.Selected != tt.selected || action != tt.action {
+				t.Errorf("选中 %d、动作 %d，期望 %d、%d", m.Selected, action, tt.selected, tt.action)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := newMenu()
+	m.Selected = 1
+	m.Status = "状态栏"
+
+	var out strings.Builder
+	if err := m.Render(&out, 20, 10); err != nil {
+		t.Fatal(err)
+	}
+	frame := strings.TrimPrefix(out.String(), clearScreen)
+	lines := strings.Split(frame, "\n")
+	if len(lines) != 10 {
+		t.Fatalf("画面有 %d 行，期望 10:\n%s", len(lines), frame)
+	}
+	if want := reverse + padRight("  2. 猜数字", 20) + reset; lines[3] != want {
+		t.Errorf("选中行 = %q，期望 %q", lines[3], want)
+	}
+	if lines[9] != "状态栏" {
+		t.Errorf("最后一行 = %q，期望状态栏", lines[9])
+	}
+}
+
+func TestOutputScroll(t *testing.T) {
+	m := newMenu()
+	var text strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&text, "line%d\n", i)
+	}
+	m.SetOutput(text.String())
+
+	var out strings.Builder
+	// 标题2行 + 3项 + 分隔线1行 + 状态栏1行，剩下3行是输出区
+	m.Render(&out, 40, 10)
+	if !strings.Contains(out.String(), "line20") || strings.Contains(out.String(), "line17") {
+		t.Errorf("初始应显示最后3行:\n%s", out.String())
+	}
+
+	m.HandleKey(Key{Code: KeyPageUp})
+	out.Reset()
+	m.Render(&out, 40, 10)
+	if !strings.Contains(out.String(), "line17") || strings.Contains(out.String(), "line18") {
+		t.Errorf("向上翻一页后应显示 line15-17:\n%s", out.String())
+	}
+
+	m.HandleKey(Key{Code: KeyHome})
+	out.Reset()
+	m.Render(&out, 40, 10)
+	if !strings.Contains(out.String(), "line1\n") {
+		t.Errorf("Home后应显示第一行:\n%s", out.String())
+	}
+
+	m.HandleKey(Key{Code: KeyEnd})
+	if m.scroll != 0 {
+		t.Errorf("End后 scroll = %d，期望 0", m.scroll)
+	}
+}
+
+func TestRunWithFakeTerminal(t *testing.T) {
+	term := &FakeTerminal{
+		Keys:   []Key{{Code: KeyDown}, {Code: KeyEnter}, {Code: KeyRune, Rune: 'q'}},
+		Input:  strings.NewReader("42\n"),
+		Width:  40,
+		Height: 12,
+	}
+	m := newMenu()
+
+	var ran []int
+	err := Run(term, m, func(i int, in io.Reader, out io.Writer) error {
+		if !term.Suspended() {
+			t.Error("运行练习时终端应处于暂停状态")
+		}
+		ran = append(ran, i)
+		data, _ := io.ReadAll(in)
+		fmt.Fprintf(out, "读到 %s", data)
+		return errors.New("出错了")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ran) != 1 || ran[0] != 1 {
+		t.Errorf("运行了 %v，期望 [1]", ran)
+	}
+	if term.Suspended() {
+		t.Error("练习结束后终端应恢复")
+	}
+	frame := term.LastFrame()
+	if !strings.Contains(frame, "读到 42") || !strings.Contains(frame, "出错了") {
+		t.Errorf("输出区应包含练习的输出和错误:\n%s", frame)
+	}
+}
+
+func TestRunStopsAtEOF(t *testing.T) {
+	term := &FakeTerminal{}
+	err := Run(term, newMenu(), func(int, io.Reader, io.Writer) error {
+		t.Error("没有按键时不应运行任何练习")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("按键用完时 Run 返回 %v，期望 nil", err)
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// RawTerminal 处于raw模式的真实终端
+type RawTerminal struct {
+	in     *os.File
+	out    *os.File
+	reader *bufio.Reader
+	saved  *termState
+}
+
+// Open 让in进入raw模式并切换到全屏界面，使用完毕后必须调用Close恢复终端
+func Open(in, out *os.File) (*RawTerminal, error) {
+	t := &RawTerminal{
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+	}
+	if err := t.Resume(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *RawTerminal) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+// ReadKey 实现 Terminal 接口
+func (t *RawTerminal) ReadKey() (Key, error) {
+	return readKey(t.reader)
+}
+
+// Size 实现 Terminal 接口，获取失败时返回80x24
+func (t *RawTerminal) Size() (int, int) {
+	w, h, err := termSize(t.out.Fd())
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
+
+// Suspend 恢复终端原来的模式，返回与按键共享缓冲区的Reader
+func (t *RawTerminal) Suspend() (io.Reader, error) {
+	if t.saved == nil {
+		return t.reader, nil
+	}
+	io.WriteString(t.out, leaveAlt)
+	err := restoreTerm(t.in.Fd(), t.saved)
+	t.saved = nil
+	return t.reader, err
+}
+
+// Resume 进入raw模式和备用屏幕
+func (t *RawTerminal) Resume() error {
+	if t.saved != nil {
+		return nil
+	}
+	saved, err := enableRaw(t.in.Fd())
+	if err != nil {
+		return err
+	}
+	t.saved = saved
+	_, err = io.WriteString(t.out, enterAlt)
+	return err
+}
+
+// Close 恢复终端
+func (t *RawTerminal) Close() error {
+	_, err := t.Suspend()
+	return err
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+)
+
+// StreamTerminal 不依赖真实终端的 Terminal：按键从r中解析（字节格式与真实终端相同），
+// 画面写到w。用于从文件读取按键（-tui 配合 -input）或录制会话
+type StreamTerminal struct {
+	Width, Height int // 为0时使用80x24
+
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+// NewStreamTerminal 创建从r读取按键、向w输出画面的终端
+func NewStreamTerminal(r io.Reader, w io.Writer) *StreamTerminal {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(r)
+	}
+	return &StreamTerminal{reader: reader, out: w}
+}
+
+func (t *StreamTerminal) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+// ReadKey 实现 Terminal 接口
+func (t *StreamTerminal) ReadKey() (Key, error) {
+	return readKey(t.reader)
+}
+
+// Size 实现 Terminal 接口
+func (t *StreamTerminal) Size() (int, int) {
+	if t.Width == 0 || t.Height == 0 {
+		return 80, 24
+	}
+	return t.Width, t.Height
+}
+
+// Suspend 实现 Terminal 接口，返回与按键共享缓冲区的Reader
+func (t *StreamTerminal) Suspend() (io.Reader, error) {
+	return t.reader, nil
+}
+
+// Resume 实现 Terminal 接口
+func (t *StreamTerminal) Resume() error {
+	return nil
+}
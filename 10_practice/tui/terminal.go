@@ -0,0 +1,125 @@
+// Package tui 实现练习菜单的全屏终端界面
+//
+// 界面逻辑只依赖抽象的 Terminal 接口，真实终端由 Open 创建（进入raw模式），
+// 测试时可以用 FakeTerminal 代替
+package tui
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// KeyCode 按键类型
+type KeyCode int
+
+const (
+	KeyRune     KeyCode = iota // 普通字符，见 Key.Rune
+	KeyUp                      // ↑
+	KeyDown                    // ↓
+	KeyPageUp                  // PgUp
+	KeyPageDown                // PgDn
+	KeyHome                    // Home
+	KeyEnd                     // End
+	KeyEnter                   // 回车
+	KeyEsc                     // Esc
+	KeyCtrlC                   // Ctrl+C
+)
+
+// Key 一次按键
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// Terminal 抽象终端
+type Terminal interface {
+	io.Writer
+
+	// ReadKey 阻塞读取一次按键
+	ReadKey() (Key, error)
+	// Size 返回终端的列数和行数
+	Size() (width, height int)
+	// Suspend 暂时退出全屏模式，返回按行读取用户输入的Reader
+	Suspend() (io.Reader, error)
+	// Resume 重新进入全屏模式
+	Resume() error
+}
+
+// ANSI控制序列
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reverse     = "\x1b[7m"
+	bold        = "\x1b[1m"
+	reset       = "\x1b[0m"
+	enterAlt    = "\x1b[?1049h\x1b[?25l" // 切换到备用屏幕并隐藏光标
+	leaveAlt    = "\x1b[?25h\x1b[?1049l" // 显示光标并回到主屏幕
+)
+
+// readKey 从r中解析一次按键，支持常见的方向键和翻页键转义序列
+func readKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 3:
+		return Key{Code: KeyCtrlC}, nil
+	case 0x1b:
+		// 单独的Esc后面不会紧跟其他字节
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEsc}, nil
+		}
+		return readEscape(r)
+	}
+
+	if b < utf8.RuneSelf {
+		return Key{Code: KeyRune, Rune: rune(b)}, nil
+	}
+	r.UnreadByte()
+	ch, _, err := r.ReadRune()
+	return Key{Code: KeyRune, Rune: ch}, err
+}
+
+// readEscape 解析 ESC [ ... 形式的序列
+func readEscape(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Code: KeyEsc}, nil
+	}
+
+	var seq []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		seq = append(seq, c)
+		if c >= 0x40 && c <= 0x7e { // 终止字节
+			break
+		}
+	}
+
+	switch string(seq) {
+	case "A":
+		return Key{Code: KeyUp}, nil
+	case "B":
+		return Key{Code: KeyDown}, nil
+	case "H", "1~":
+		return Key{Code: KeyHome}, nil
+	case "F", "4~":
+		return Key{Code: KeyEnd}, nil
+	case "5~":
+		return Key{Code: KeyPageUp}, nil
+	case "6~":
+		return Key{Code: KeyPageDown}, nil
+	}
+	// 不认识的序列当作Esc处理
+	return Key{Code: KeyEsc}, nil
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Key
+	}{
+		{"普通字符", "ab", []Key{{Code: KeyRune, Rune: 'a'}, {Code: KeyRune, Rune: 'b'}}},
+		{"中文", "好", []Key{{Code: KeyRune, Rune: '好'}}},
+		{"回车", "\r\n", []Key{{Code: KeyEnter}, {Code: KeyEnter}}},
+		{"Ctrl+C", "\x03", []Key{{Code: KeyCtrlC}}},
+		{"方向键", "\x1b[A\x1b[B\x1bOA", []Key{{Code: KeyUp}, {Code: KeyDown}, {Code: KeyUp}}},
+		{"翻页", "\x1b[5~\x1b[6~", []Key{{Code: KeyPageUp}, {Code: KeyPageDown}}},
+		{"Home和End", "\x1b[H\x1b[1~\x1b[F\x1b[4~",
+			[]Key{{Code: KeyHome}, {Code: KeyHome}, {Code: KeyEnd}, {Code: KeyEnd}}},
+		{"单独的Esc", "\x1b", []Key{{Code: KeyEsc}}},
+		{"不认识的序列", "\x1b[99~x", []Key{{Code: KeyEsc}, {Code: KeyRune, Rune: 'x'}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+			for i, want := range tt.want {
+				got, err := readKey(r)
+				if err != nil {
+					t.Fatalf("第%d个按键: %v", i+1, err)
+				}
+				if got != want {
+					t.Errorf("第%d个按键 = %+v，期望 %+v", i+1, got, want)
+				}
+			}
+			if _, err := readKey(r); err != io.EOF {
+				t.Errorf("读完后返回 %v，期望 io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		trunc string
+		pad   string
+	}{
+		{"hello", 3, "hel", "hello"},
+		{"hello", 7, "hello", "hello  "},
+		{"中文字", 4, "中文", "中文字"},
+		{"中文字", 5, "中文", "中文字"},
+		{"a中", 4, "a中", "a中 "},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.trunc {
+			t.Errorf("truncate(%q, %d) = %q，期望 %q", tt.s, tt.width, got, tt.trunc)
+		}
+		if got := padRight(tt.s, tt.width); got != tt.pad {
+			t.Errorf("padRight(%q, %d) = %q，期望 %q", tt.s, tt.width, got, tt.pad)
+		}
+	}
+}
+
+func TestStreamTerminal(t *testing.T) {
+	var out strings.Builder
+	term := NewStreamTerminal(strings.NewReader("j\r2+3\n"), &out)
+
+	if k, _ := term.ReadKey(); k != (Key{Code: KeyRune, Rune: 'j'}) {
+		t.Errorf("第一个按键 = %+v", k)
+	}
+	if k, _ := term.ReadKey(); k.Code != KeyEnter {
+		t.Errorf("第二个按键 = %+v，期望回车", k)
+	}
+
+	// 暂停后返回的Reader与按键共享缓冲区，不会丢失已经读入缓冲区的内容
+	in, err := term.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	if line != "2+3\n" {
+		t.Errorf("暂停后读到 %q，期望 %q", line, "2+3\n")
+	}
+
+	term.Write([]byte("画面"))
+	if out.String() != "画面" {
+		t.Errorf("输出 = %q", out.String())
+	}
+}
@@ -0,0 +1,9 @@
+package tui
+
+import "syscall"
+
+// 获取和设置终端属性的ioctl请求号，不同架构的取值不同（如ppc64、mips），因此使用syscall包中的定义
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
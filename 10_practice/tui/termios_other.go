@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package tui
+
+import "errors"
+
+type termState struct{}
+
+var errUnsupported = errors.New("当前平台不支持终端raw模式")
+
+func enableRaw(fd uintptr) (*termState, error) { return nil, errUnsupported }
+
+func restoreTerm(fd uintptr, state *termState) error { return errUnsupported }
+
+func termSize(fd uintptr) (int, int, error) { return 0, 0, errUnsupported }
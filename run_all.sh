@@ -38,16 +38,16 @@ for chapter in "${chapters[@]}"; do
     
     if [ -f "$file" ]; then
         # 运行Go文件
+        args=()
         if [[ "$file" == "10_practice/practice.go" ]]; then
-            echo "⚠️  注意：实战练习是交互式程序，请手动运行："
-            echo "   go run $file"
+            # 实战练习默认是交互式菜单，这里以非交互模式运行计算器练习
+            args=(-exercise calculator)
+        fi
+        go run "$file" "${args[@]}"
+        if [ $? -eq 0 ]; then
+            echo "✅ 运行成功"
         else
-            go run "$file"
-            if [ $? -eq 0 ]; then
-                echo "✅ 运行成功"
-            else
-                echo "❌ 运行失败"
-            fi
+            echo "❌ 运行失败"
         fi
     else
         echo "❌ 文件不存在：$file"